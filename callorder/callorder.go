@@ -0,0 +1,105 @@
+// Package callorder records the order of the calls to several mocks generated with --order, to check it in tests, e.g.
+//
+//	var calls callorder.Recorder
+//	tx.RecordOrder(&calls)
+//	store.RecordOrder(&calls)
+//	...
+//	calls.InOrder(t, tx.BeginCalled(), store.PutCalled(), tx.CommitCalled())
+package callorder
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Recorder records the calls to the mocks registered with it, in the order they were made. The zero value is ready to use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Call is a call to a mock
+type Call struct {
+	// Mock is the mock that was called, e.g. a *MockTx
+	Mock interface{}
+	// Method is the method that was called, e.g. `MockTx.Begin`
+	Method string
+	// Args is the call with its arguments, as recorded by the mock, e.g. a MockStorePutCall
+	Args interface{}
+}
+
+// String describes the call, e.g. `MockStore.Put {Key:a}`
+func (call Call) String() string {
+	return fmt.Sprintf("%s %+v", call.Method, call.Args)
+}
+
+// Step is a call to a method of a mock, for InOrder
+type Step struct {
+	// Mock is the mock expected to be called, e.g. a *MockTx
+	Mock interface{}
+	// Method is the method expected to be called, e.g. `MockTx.Begin`
+	Method string
+}
+
+// matches returns true if the call is to the step's method of the step's mock
+func (step Step) matches(call Call) bool {
+	return call.Mock == step.Mock && call.Method == step.Method
+}
+
+// Record records a call. It is called by the generated mocks.
+func (recorder *Recorder) Record(mock interface{}, method string, args interface{}) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.calls = append(recorder.calls, Call{mock, method, args})
+}
+
+// Calls returns the calls recorded, in the order they were made
+func (recorder *Recorder) Calls() []Call {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]Call(nil), recorder.calls...)
+}
+
+// InOrder checks that the calls of the steps were made in the order of the steps. Other calls can be made before, between and after them.
+// If they weren't, it fails the test with the steps and the calls recorded, and returns false.
+func (recorder *Recorder) InOrder(t testing.TB, steps ...Step) bool {
+	t.Helper()
+
+	calls := recorder.Calls()
+	done := 0
+	for _, call := range calls {
+		if done < len(steps) && steps[done].matches(call) {
+			done++
+		}
+	}
+	if done == len(steps) {
+		return true
+	}
+
+	message := new(strings.Builder)
+	if done == 0 {
+		fmt.Fprintf(message, "calls not in the expected order: %s was not called\n", steps[done].Method)
+	} else {
+		fmt.Fprintf(message, "calls not in the expected order: %s was not called after %s\n", steps[done].Method, steps[done-1].Method)
+	}
+	message.WriteString("expected order:\n")
+	for i, step := range steps {
+		fmt.Fprintf(message, "\t%d. %s", i+1, step.Method)
+		if i == done {
+			message.WriteString(" <- missing")
+		}
+		message.WriteString("\n")
+	}
+	message.WriteString("calls:\n")
+	if len(calls) == 0 {
+		message.WriteString("\tnone\n")
+	}
+	for i, call := range calls {
+		fmt.Fprintf(message, "\t%d. %s\n", i+1, call)
+	}
+	t.Error(strings.TrimRight(message.String(), "\n"))
+
+	return false
+}
@@ -0,0 +1,75 @@
+package callorder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Error(args ...interface{}) {
+	tb.errors = append(tb.errors, args[0].(string))
+}
+
+// the mocks have fields, as pointers to distinct zero-size values can be equal
+type mockTx struct {
+	name string
+}
+
+type mockStore struct {
+	name string
+}
+
+type putCall struct {
+	Key string
+}
+
+func TestRecorder_InOrder(t *testing.T) {
+	tx, store := &mockTx{}, &mockStore{}
+	begin := Step{tx, "MockTx.Begin"}
+	put := Step{store, "MockStore.Put"}
+	commit := Step{tx, "MockTx.Commit"}
+
+	var recorder Recorder
+	recorder.Record(tx, "MockTx.Begin", struct{}{})
+	recorder.Record(store, "MockStore.Get", struct{}{})
+	recorder.Record(store, "MockStore.Put", putCall{"a"})
+	recorder.Record(tx, "MockTx.Commit", struct{}{})
+
+	assert.Len(t, recorder.Calls(), 4)
+	assert.True(t, recorder.InOrder(t, begin, put, commit))
+	assert.True(t, recorder.InOrder(t, begin, commit))
+	assert.True(t, recorder.InOrder(t))
+
+	t.Run("out of order", func(t *testing.T) {
+		tb := &fakeTB{}
+		assert.False(t, recorder.InOrder(tb, put, begin))
+		assert.Equal(t, []string{`calls not in the expected order: MockTx.Begin was not called after MockStore.Put
+expected order:
+	1. MockStore.Put
+	2. MockTx.Begin <- missing
+calls:
+	1. MockTx.Begin {}
+	2. MockStore.Get {}
+	3. MockStore.Put {Key:a}
+	4. MockTx.Commit {}`}, tb.errors)
+	})
+
+	t.Run("other mock", func(t *testing.T) {
+		tb := &fakeTB{}
+		assert.False(t, recorder.InOrder(tb, Step{&mockTx{}, "MockTx.Begin"}))
+		assert.Contains(t, tb.errors[0], "calls not in the expected order: MockTx.Begin was not called\n")
+	})
+
+	t.Run("no calls", func(t *testing.T) {
+		tb := &fakeTB{}
+		assert.False(t, new(Recorder).InOrder(tb, begin))
+		assert.Contains(t, tb.errors[0], "calls:\n\tnone")
+	})
+}
@@ -0,0 +1,364 @@
+// Package cli is the go-mockgen-tool command line interface.
+//
+// It can be used to build a custom go-mockgen-tool with extensions:
+//
+//	func main() {
+//		mockgen.RegisterExtension(myExtension{})
+//		cli.Main()
+//	}
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jamesrr39/go-mockgen-tool/mockgen"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Main parses the command line arguments and runs go-mockgen-tool, with the extensions registered with mockgen.RegisterExtension
+func Main() {
+	var interfaceName, position string
+	var flatten bool
+	var flags generateFlags
+	kingpin.Flag("type", "name of the interface type, or of a function type. Several interfaces can be given, separated by commas, to generate one mock implementing all of them, e.g. 'Store,io.Closer,Health' (with --mock-name)").StringVar(&interfaceName)
+	kingpin.Flag("flatten", "add the methods of embedded interfaces to the mock, rather than embedding the interfaces. Their declarations are found through the module, go.work and replace directives, and the module cache").BoolVar(&flatten)
+	kingpin.Flag("pos", "position of the interface to mock, for editor integrations: <file>:#<byte offset> or <file>:<line>:<column>. The mock is written to stdout").StringVar(&position)
+
+	generateCmd := kingpin.Command("generate", "generate a mock for the interface (default)").Default()
+	generateCmd.Flag("o", "out file. File to write the generated type to. Defaults to <typename>_mock.go").StringVar(&flags.outFilePath)
+	generateCmd.Flag("out-package", "name of the package to generate the mock in, if it isn't the interface's package, e.g. 'mocks' or 'example_test'").StringVar(&flags.options.OutPackage)
+	generateCmd.Flag("json", "with --pos, write the mock to stdout as a JSON edit instead of plain text").BoolVar(&flags.jsonOutput)
+	generateCmd.Flag("template", "path to a text/template file to render the mock with, instead of the built-in template").StringVar(&flags.templateFilePath)
+	generateCmd.Flag("mock-prefix", "prefix added to the interface name to make the mock type name. If neither --mock-prefix nor --mock-suffix is given, the prefix is Mock, or Spy with --spy").StringVar(&flags.options.Naming.MockPrefix)
+	generateCmd.Flag("mock-suffix", "suffix added to the interface name to make the mock type name, e.g. Fake for HealthFake").StringVar(&flags.options.Naming.MockSuffix)
+	generateCmd.Flag("mock-name", "name of the mock type. Overrides --mock-prefix and --mock-suffix").StringVar(&flags.options.Naming.MockName)
+	generateCmd.Flag("field-suffix", "suffix added to method names to make the names of the fields setting their behaviour").Default(mockgen.DefaultNaming.FieldSuffix).StringVar(&flags.options.Naming.FieldSuffix)
+	generateCmd.Flag("receiver", "receiver name used in the mock's methods").Default(mockgen.DefaultNaming.Receiver).StringVar(&flags.options.Naming.Receiver)
+	generateCmd.Flag("methods", "comma separated names of the methods to mock, e.g. 'Get,Put'. The other methods are delegated to an implementation of the interface passed to the mock's constructor").StringVar(&flags.methods)
+	generateCmd.Flag("exclude", "comma separated names of the methods not to mock, and delegate instead (see --methods)").StringVar(&flags.excludeMethods)
+	generateCmd.Flag("from-struct", "concrete type to generate an interface from, along with its mock, e.g. '*pgstore.Store'. The interface has the exported method set of the type, and is named with --type, or after the type").StringVar(&flags.fromStruct)
+	generateCmd.Flag("interface-methods", "with --from-struct, comma separated names of the only methods to include in the interface").StringVar(&flags.extractOptions.methods)
+	generateCmd.Flag("interface-exclude", "with --from-struct, comma separated names of the methods to leave out of the interface").StringVar(&flags.extractOptions.excludeMethods)
+	generateCmd.Flag("only-used", "with --from-struct, only include the methods that the package in the current directory calls on the type, found by type-checking it").BoolVar(&flags.extractOptions.onlyUsed)
+	generateCmd.Flag("interface-out", "with --from-struct, file to write the interface to. Defaults to <typename>.go").StringVar(&flags.extractOptions.outFilePath)
+	generateCmd.Flag("header-file", "path to a file with text, e.g. a license, to add to the top of the mock file").StringVar(&flags.headerFilePath)
+	generateCmd.Flag("on-unset", "what the mock's methods do when their fields aren't set: panic, return the zero values (zero), or return the zero values with an error for a last error result (error)").Default(string(mockgen.OnUnsetPanic)).EnumVar(&flags.onUnset, string(mockgen.OnUnsetPanic), string(mockgen.OnUnsetZero), string(mockgen.OnUnsetError))
+	generateCmd.Flag("testing", "add a constructor taking the test's testing.TB, which fails the test when methods without fields are called, or fields are set for methods that are never called. The mock file imports the testing package, so it is usually a _test.go file (see -o)").BoolVar(&flags.options.Testing)
+	generateCmd.Flag("expect", "add typed expectations, e.g. 'mock.ExpectGet(match.Eq(\"key\")).Return(item, nil)', with the matchers of the github.com/jamesrr39/go-mockgen-tool/match package. Implies --testing").BoolVar(&flags.options.Expectations)
+	generateCmd.Flag("returns", "add methods setting the results of the calls to each method, e.g. 'mock.WheelCountReturns(4, nil)', 'mock.WheelCountReturnsOnCall(0, 0, errTimeout)' and 'mock.WheelCountReturnsSequence(...)'").BoolVar(&flags.options.Returns)
+	generateCmd.Flag("order", "add a RecordOrder method, recording the calls to the mock with a callorder.Recorder of the github.com/jamesrr39/go-mockgen-tool/callorder package, to check the order of the calls across mocks, e.g. 'calls.InOrder(t, tx.BeginCalled(), store.PutCalled())'").BoolVar(&flags.options.Order)
+	generateCmd.Flag("sync", "add setters for the fields of the mock, e.g. 'mock.SetWheelCountFunc(...)', synchronized with the calls to the mock, so that the fields can be set while the mock is called from other goroutines").BoolVar(&flags.options.Synchronized)
+	generateCmd.Flag("spy", "generate a spy, e.g. 'SpyVehicle' created with 'NewSpyVehicle(real)', whose methods delegate to the real implementation when their fields aren't set, and record the results of the calls along with the arguments").BoolVar(&flags.options.Spy)
+	generateCmd.Flag("line-directives", "add //line directives so that panics and coverage in the mock's methods point to the interface declaration").BoolVar(&flags.options.LineDirectives)
+	generateCmd.Flag("go-version", "Go version to generate the mock for, e.g. 1.18. Defaults to the go directive of the module's go.mod file, or the version of the Go toolchain outside of a module").StringVar(&flags.options.GoVersion)
+	generateCmd.Flag("build-constraint", "build constraint for the mock file, e.g. 'testmocks'. Combined with the build constraint of the interface's file").StringVar(&flags.options.BuildConstraint)
+
+	inspectCmd := kingpin.Command("inspect", "write a JSON description of the interface to stdout")
+
+	switch kingpin.Parse() {
+	case generateCmd.FullCommand():
+		generate(interfaceName, position, flatten, flags)
+	case inspectCmd.FullCommand():
+		inspect(interfaceName, position, flatten)
+	}
+}
+
+// generateFlags are the flags of the generate command. The flags that map directly to mockgen options are set in options.
+type generateFlags struct {
+	options                                       mockgen.Options
+	outFilePath, templateFilePath, headerFilePath string
+	methods, excludeMethods                       string
+	jsonOutput                                    bool
+	onUnset                                       string
+	fromStruct                                    string
+	extractOptions                                extractFlags
+}
+
+// extractFlags are the flags for generating an interface with --from-struct
+type extractFlags struct {
+	methods, excludeMethods, outFilePath string
+	onlyUsed                             bool
+}
+
+func generate(interfaceName, position string, flatten bool, flags generateFlags) {
+	if flags.fromStruct != "" {
+		interfaceName = extractInterface(interfaceName, position, flags)
+	}
+
+	typeData, interfaceName, dirPath, resolver := loadTypeData(interfaceName, position, flatten)
+
+	if len(typeData.Interfaces) != 0 {
+		if flags.options.Naming.MockName == "" {
+			kingpin.Fatalf("--mock-name is required to mock several interfaces at once")
+		}
+		// the mock is named after itself, as there is no single interface
+		interfaceName = flags.options.Naming.MockName
+	}
+
+	outFilePath, jsonOutput := flags.outFilePath, flags.jsonOutput
+	if outFilePath == "" {
+		outFilePath = filepath.Join(dirPath, fmt.Sprintf("%s_mock.go", strings.ToLower(interfaceName)))
+	}
+
+	options := flags.options
+	options.Extensions = mockgen.RegisteredExtensions()
+	options.MockFilePath = outFilePath
+	options.Methods = splitList(flags.methods)
+	options.ExcludeMethods = splitList(flags.excludeMethods)
+	options.OnUnset = mockgen.OnUnset(flags.onUnset)
+	if options.GoVersion == "" {
+		options.GoVersion = targetGoVersion(dirPath)
+	}
+	if options.OutPackage != "" {
+		importPath, err := resolver.ImportPathForDir(dirPath)
+		if err != nil {
+			log.Fatalf("couldn't find the import path of the interface's package: %s\n", err)
+		}
+		options.PackageImportPath = importPath
+	}
+	if templateFilePath := flags.templateFilePath; templateFilePath != "" {
+		templateText, err := ioutil.ReadFile(templateFilePath)
+		if err != nil {
+			log.Fatalf("couldn't read template %q. Error: %q", templateFilePath, err)
+		}
+		options.Template = string(templateText)
+	}
+
+	if headerFilePath := flags.headerFilePath; headerFilePath != "" {
+		headerText, err := ioutil.ReadFile(headerFilePath)
+		if err != nil {
+			log.Fatalf("couldn't read header file %q. Error: %q", headerFilePath, err)
+		}
+		options.Header = string(headerText)
+	}
+
+	generatedMock, err := mockgen.GenerateMock(interfaceName, typeData, options)
+	if err != nil {
+		log.Fatalf("error generating mock: %s\n", err)
+	}
+
+	if position == "" {
+		err = ioutil.WriteFile(outFilePath, []byte(generatedMock.Text), 0664)
+		if err != nil {
+			log.Fatalf("error writing mock to %q: %s\n", outFilePath, err)
+		}
+		for _, file := range generatedMock.ExtraFiles {
+			filePath := filepath.Join(filepath.Dir(outFilePath), file.Name)
+			err = ioutil.WriteFile(filePath, []byte(file.Content), 0664)
+			if err != nil {
+				log.Fatalf("error writing %q: %s\n", filePath, err)
+			}
+		}
+		return
+	}
+
+	if !jsonOutput {
+		fmt.Print(generatedMock.Text)
+		for _, file := range generatedMock.ExtraFiles {
+			fmt.Print(file.Content)
+		}
+		return
+	}
+
+	edit := jsonEdit{
+		Filename:  outFilePath,
+		Interface: interfaceName,
+		NewText:   generatedMock.Text,
+	}
+	for _, file := range generatedMock.ExtraFiles {
+		edit.ExtraFiles = append(edit.ExtraFiles, jsonFile{
+			Filename: filepath.Join(filepath.Dir(outFilePath), file.Name),
+			NewText:  file.Content,
+		})
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(edit)
+	if err != nil {
+		log.Fatalf("error writing JSON edit: %s\n", err)
+	}
+}
+
+// extractInterface writes the interface with the method set of the --from-struct type, and returns its name
+func extractInterface(interfaceName, position string, flags generateFlags) string {
+	if position != "" {
+		kingpin.Fatalf("--from-struct can't be used with --pos")
+	}
+
+	resolver, err := mockgen.NewResolver(".")
+	if err != nil {
+		log.Fatalf("error reading the module: %s\n", err)
+	}
+
+	options := mockgen.ExtractOptions{
+		InterfaceName:  interfaceName,
+		Methods:        splitList(flags.extractOptions.methods),
+		ExcludeMethods: splitList(flags.extractOptions.excludeMethods),
+		OnlyUsed:       flags.extractOptions.onlyUsed,
+	}
+	if headerFilePath := flags.headerFilePath; headerFilePath != "" {
+		headerText, err := ioutil.ReadFile(headerFilePath)
+		if err != nil {
+			log.Fatalf("couldn't read header file %q. Error: %q", headerFilePath, err)
+		}
+		options.Header = string(headerText)
+	}
+
+	extractedInterface, err := mockgen.ExtractInterface(".", flags.fromStruct, options, resolver)
+	if err != nil {
+		log.Fatalf("error generating an interface for %s: %s\n", flags.fromStruct, err)
+	}
+
+	outFilePath := flags.extractOptions.outFilePath
+	if outFilePath == "" {
+		outFilePath = fmt.Sprintf("%s.go", strings.ToLower(extractedInterface.Name))
+	}
+	err = ioutil.WriteFile(outFilePath, []byte(extractedInterface.Text), 0664)
+	if err != nil {
+		log.Fatalf("error writing interface to %q: %s\n", outFilePath, err)
+	}
+
+	return extractedInterface.Name
+}
+
+// splitList splits a comma separated list from a flag, e.g. `Get, Put`
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// targetGoVersion returns the Go version of the module the directory is in, or the version of the Go toolchain if it isn't in a module
+func targetGoVersion(dirPath string) string {
+	goVersion, err := mockgen.GoVersionForDir(dirPath)
+	if err == nil {
+		return goVersion
+	}
+	if err != mockgen.ErrNoModule {
+		log.Fatalf("couldn't find the Go version of the module: %s\n", err)
+	}
+
+	toolchainVersion := strings.Fields(runtime.Version())[0]
+	if !strings.HasPrefix(toolchainVersion, "go") {
+		// development version of Go, leave the code as it is written
+		return ""
+	}
+
+	return toolchainVersion
+}
+
+// jsonEdit describes the mock file to be written by an editor integration
+type jsonEdit struct {
+	// Filename is the file the mock would be written to without --pos
+	Filename  string `json:"filename"`
+	Interface string `json:"interface"`
+	NewText   string `json:"newText"`
+	// ExtraFiles are the files contributed by extensions
+	ExtraFiles []jsonFile `json:"extraFiles,omitempty"`
+}
+
+type jsonFile struct {
+	Filename string `json:"filename"`
+	NewText  string `json:"newText"`
+}
+
+func inspect(interfaceName, position string, flatten bool) {
+	typeData, interfaceName, _, _ := loadTypeData(interfaceName, position, flatten)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(mockgen.Inspect(interfaceName, typeData))
+	if err != nil {
+		log.Fatalf("error writing interface description: %s\n", err)
+	}
+}
+
+// loadTypeData finds the interface, either by name in the current directory, or at the position, and flattens its embedded interfaces if flatten is set.
+// It returns the interface's type data and name, the directory it was found in, and the resolver for the module the directory is in.
+func loadTypeData(interfaceName, position string, flatten bool) (*mockgen.TypeData, string, string, *mockgen.Resolver) {
+	typeData, interfaceName, dirPath := findTypeData(interfaceName, position)
+
+	resolver, err := mockgen.NewResolver(dirPath)
+	if err != nil {
+		log.Fatalf("error reading the module of %q: %s\n", dirPath, err)
+	}
+
+	if flatten {
+		err = mockgen.FlattenEmbeddedInterfaces(typeData, dirPath, resolver)
+		if err != nil {
+			log.Fatalf("error flattening %s: %s\n", interfaceName, err)
+		}
+	}
+
+	return typeData, interfaceName, dirPath, resolver
+}
+
+// findTypeData finds the interface, either by name in the current directory, or at the position.
+// It returns the interface's type data and name, and the directory it was found in.
+func findTypeData(interfaceName, position string) (*mockgen.TypeData, string, string) {
+	if strings.Contains(interfaceName, ",") {
+		if position != "" {
+			kingpin.Fatalf("several interfaces can't be given with --pos")
+		}
+
+		resolver, err := mockgen.NewResolver(".")
+		if err != nil {
+			log.Fatalf("error reading the module: %s\n", err)
+		}
+
+		typeData, err := mockgen.CompositeTypeData(".", splitList(interfaceName), resolver)
+		if err != nil {
+			log.Fatalf("error combining interfaces %s: %s\n", interfaceName, err)
+		}
+
+		return typeData, interfaceName, "."
+	}
+
+	if position != "" {
+		pos, err := mockgen.ParsePosition(position)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		sourceCode, err := ioutil.ReadFile(pos.Filename)
+		if err != nil {
+			log.Fatalf("couldn't read %q. Error: %q", pos.Filename, err)
+		}
+
+		typeData, foundInterfaceName, err := mockgen.GetMethodsForPosition(string(sourceCode), pos)
+		if err != nil {
+			log.Fatalf("error finding interface at %s: %s\n", pos, err)
+		}
+
+		if interfaceName == "" {
+			interfaceName = foundInterfaceName
+		}
+
+		return typeData, interfaceName, filepath.Dir(pos.Filename)
+	}
+
+	if interfaceName == "" {
+		kingpin.Fatalf("required flag --type not provided")
+	}
+
+	typeData, err := mockgen.GetMethodsForTypeInDir(".", interfaceName)
+	if err != nil {
+		if err == mockgen.ErrInterfaceTypeNotFound {
+			log.Fatalln("no methods found. Either there were no files with the interface name or there were no methods to mock")
+		}
+		log.Fatalf("error generating mock: %s\n", err)
+	}
+
+	return typeData, interfaceName, "."
+}
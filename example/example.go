@@ -14,11 +14,6 @@ const (
 	DriveModeFast DriveMode = iota
 )
 
-var (
-	// compile time check that *MockVehicle implements Vehicle
-	_ Vehicle = &MockVehicle{}
-)
-
 //go:generate go-mockgen-tool --type Vehicle
 type Vehicle interface {
 	Name() string
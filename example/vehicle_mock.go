@@ -3,68 +3,265 @@
 package example
 
 import (
-	"io"
 	"github.com/jamesrr39/go-mockgen-tool/example/extrapkg"
 	"github.com/jamesrr39/go-mockgen-tool/example/extrapkg2"
+	"io"
+	"sync"
 )
 
+// MockVehicle is a mock implementation of [Vehicle].
 type MockVehicle struct {
-	NameFunc         func() string 
-	WheelCountFunc   func() (int, error) 
-	test2Func        func(mode DriveMode, mode2 DriveMode) func(cargoWeightKg float64) (float64, error) 
-	GetReaderFunc    func() io.Reader 
-	DoSomethingFunc  func() 
-	DoSomething2Func func(err1 extrapkg.Error, err2 extrapkg.Error, a int) extrapkg2.Error2 
-	DoSomething3Func func(param0 extrapkg.Error, param1 int, param2 func(a, b string) extrapkg.Error) 
+	// NameFunc is called by [MockVehicle.Name].
+	NameFunc func() string
+	// WheelCountFunc is called by [MockVehicle.WheelCount].
+	WheelCountFunc func() (int, error)
+	// test2Func is called by [MockVehicle.test2].
+	test2Func func(mode DriveMode, mode2 DriveMode) func(cargoWeightKg float64) (float64, error)
+	// GetReaderFunc is called by [MockVehicle.GetReader].
+	GetReaderFunc func() io.Reader
+	// DoSomethingFunc is called by [MockVehicle.DoSomething].
+	//
+	// DoSomething is a no-return function
+	DoSomethingFunc func()
+	// DoSomething2Func is called by [MockVehicle.DoSomething2].
+	DoSomething2Func func(err1 extrapkg.Error, err2 extrapkg.Error, a int) extrapkg2.Error2
+	// DoSomething3Func is called by [MockVehicle.DoSomething3].
+	DoSomething3Func func(param0 extrapkg.Error, param1 int, param2 func(a, b string) extrapkg.Error)
 	io.Writer
 	SecondInterface
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Name         []MockVehicleNameCall
+		WheelCount   []MockVehicleWheelCountCall
+		test2        []MockVehicleTest2Call
+		GetReader    []MockVehicleGetReaderCall
+		DoSomething  []MockVehicleDoSomethingCall
+		DoSomething2 []MockVehicleDoSomething2Call
+		DoSomething3 []MockVehicleDoSomething3Call
+	}
 }
 
+var (
+	// compile time checks that *MockVehicle implements the interface and the interfaces it embeds
+	_ Vehicle         = &MockVehicle{}
+	_ io.Writer       = &MockVehicle{}
+	_ SecondInterface = &MockVehicle{}
+)
+
+// Name implements [Vehicle.Name] by calling NameFunc.
 func (o *MockVehicle) Name() string {
+	o.mockMu.Lock()
+	o.mockCalls.Name = append(o.mockCalls.Name, MockVehicleNameCall{})
+	o.mockMu.Unlock()
+
 	if o.NameFunc == nil {
 		panic("NameFunc not defined")
 	}
 	return o.NameFunc()
 }
 
+// MockVehicleNameCall is a call to [MockVehicle.Name], with its arguments.
+type MockVehicleNameCall struct{}
+
+// NameCalls returns the calls to [MockVehicle.Name], in the order they were made.
+func (o *MockVehicle) NameCalls() []MockVehicleNameCall {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleNameCall(nil), o.mockCalls.Name...)
+}
+
+// NameCallCount returns the number of calls to [MockVehicle.Name].
+func (o *MockVehicle) NameCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.Name)
+}
+
+// WheelCount implements [Vehicle.WheelCount] by calling WheelCountFunc.
 func (o *MockVehicle) WheelCount() (int, error) {
+	o.mockMu.Lock()
+	o.mockCalls.WheelCount = append(o.mockCalls.WheelCount, MockVehicleWheelCountCall{})
+	o.mockMu.Unlock()
+
 	if o.WheelCountFunc == nil {
 		panic("WheelCountFunc not defined")
 	}
 	return o.WheelCountFunc()
 }
 
+// MockVehicleWheelCountCall is a call to [MockVehicle.WheelCount], with its arguments.
+type MockVehicleWheelCountCall struct{}
+
+// WheelCountCalls returns the calls to [MockVehicle.WheelCount], in the order they were made.
+func (o *MockVehicle) WheelCountCalls() []MockVehicleWheelCountCall {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleWheelCountCall(nil), o.mockCalls.WheelCount...)
+}
+
+// WheelCountCallCount returns the number of calls to [MockVehicle.WheelCount].
+func (o *MockVehicle) WheelCountCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.WheelCount)
+}
+
+// test2 implements [Vehicle.test2] by calling test2Func.
 func (o *MockVehicle) test2(mode DriveMode, mode2 DriveMode) func(cargoWeightKg float64) (float64, error) {
+	o.mockMu.Lock()
+	o.mockCalls.test2 = append(o.mockCalls.test2, MockVehicleTest2Call{Mode: mode, Mode2: mode2})
+	o.mockMu.Unlock()
+
 	if o.test2Func == nil {
 		panic("test2Func not defined")
 	}
 	return o.test2Func(mode, mode2)
 }
 
+// MockVehicleTest2Call is a call to [MockVehicle.test2], with its arguments.
+type MockVehicleTest2Call struct {
+	Mode  DriveMode
+	Mode2 DriveMode
+}
+
+// test2Calls returns the calls to [MockVehicle.test2], in the order they were made.
+func (o *MockVehicle) test2Calls() []MockVehicleTest2Call {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleTest2Call(nil), o.mockCalls.test2...)
+}
+
+// test2CallCount returns the number of calls to [MockVehicle.test2].
+func (o *MockVehicle) test2CallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.test2)
+}
+
+// GetReader implements [Vehicle.GetReader] by calling GetReaderFunc.
 func (o *MockVehicle) GetReader() io.Reader {
+	o.mockMu.Lock()
+	o.mockCalls.GetReader = append(o.mockCalls.GetReader, MockVehicleGetReaderCall{})
+	o.mockMu.Unlock()
+
 	if o.GetReaderFunc == nil {
 		panic("GetReaderFunc not defined")
 	}
 	return o.GetReaderFunc()
 }
 
+// MockVehicleGetReaderCall is a call to [MockVehicle.GetReader], with its arguments.
+type MockVehicleGetReaderCall struct{}
+
+// GetReaderCalls returns the calls to [MockVehicle.GetReader], in the order they were made.
+func (o *MockVehicle) GetReaderCalls() []MockVehicleGetReaderCall {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleGetReaderCall(nil), o.mockCalls.GetReader...)
+}
+
+// GetReaderCallCount returns the number of calls to [MockVehicle.GetReader].
+func (o *MockVehicle) GetReaderCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.GetReader)
+}
+
+// DoSomething implements [Vehicle.DoSomething] by calling DoSomethingFunc.
+//
+// DoSomething is a no-return function
 func (o *MockVehicle) DoSomething() {
+	o.mockMu.Lock()
+	o.mockCalls.DoSomething = append(o.mockCalls.DoSomething, MockVehicleDoSomethingCall{})
+	o.mockMu.Unlock()
+
 	if o.DoSomethingFunc == nil {
 		panic("DoSomethingFunc not defined")
 	}
 	o.DoSomethingFunc()
 }
 
+// MockVehicleDoSomethingCall is a call to [MockVehicle.DoSomething], with its arguments.
+type MockVehicleDoSomethingCall struct{}
+
+// DoSomethingCalls returns the calls to [MockVehicle.DoSomething], in the order they were made.
+func (o *MockVehicle) DoSomethingCalls() []MockVehicleDoSomethingCall {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleDoSomethingCall(nil), o.mockCalls.DoSomething...)
+}
+
+// DoSomethingCallCount returns the number of calls to [MockVehicle.DoSomething].
+func (o *MockVehicle) DoSomethingCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.DoSomething)
+}
+
+// DoSomething2 implements [Vehicle.DoSomething2] by calling DoSomething2Func.
 func (o *MockVehicle) DoSomething2(err1 extrapkg.Error, err2 extrapkg.Error, a int) extrapkg2.Error2 {
+	o.mockMu.Lock()
+	o.mockCalls.DoSomething2 = append(o.mockCalls.DoSomething2, MockVehicleDoSomething2Call{Err1: err1, Err2: err2, A: a})
+	o.mockMu.Unlock()
+
 	if o.DoSomething2Func == nil {
 		panic("DoSomething2Func not defined")
 	}
 	return o.DoSomething2Func(err1, err2, a)
 }
 
+// MockVehicleDoSomething2Call is a call to [MockVehicle.DoSomething2], with its arguments.
+type MockVehicleDoSomething2Call struct {
+	Err1 extrapkg.Error
+	Err2 extrapkg.Error
+	A    int
+}
+
+// DoSomething2Calls returns the calls to [MockVehicle.DoSomething2], in the order they were made.
+func (o *MockVehicle) DoSomething2Calls() []MockVehicleDoSomething2Call {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleDoSomething2Call(nil), o.mockCalls.DoSomething2...)
+}
+
+// DoSomething2CallCount returns the number of calls to [MockVehicle.DoSomething2].
+func (o *MockVehicle) DoSomething2CallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.DoSomething2)
+}
+
+// DoSomething3 implements [Vehicle.DoSomething3] by calling DoSomething3Func.
 func (o *MockVehicle) DoSomething3(param0 extrapkg.Error, param1 int, param2 func(a, b string) extrapkg.Error) {
+	o.mockMu.Lock()
+	o.mockCalls.DoSomething3 = append(o.mockCalls.DoSomething3, MockVehicleDoSomething3Call{Param0: param0, Param1: param1, Param2: param2})
+	o.mockMu.Unlock()
+
 	if o.DoSomething3Func == nil {
 		panic("DoSomething3Func not defined")
 	}
 	o.DoSomething3Func(param0, param1, param2)
 }
+
+// MockVehicleDoSomething3Call is a call to [MockVehicle.DoSomething3], with its arguments.
+type MockVehicleDoSomething3Call struct {
+	Param0 extrapkg.Error
+	Param1 int
+	Param2 func(a, b string) extrapkg.Error
+}
+
+// DoSomething3Calls returns the calls to [MockVehicle.DoSomething3], in the order they were made.
+func (o *MockVehicle) DoSomething3Calls() []MockVehicleDoSomething3Call {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockVehicleDoSomething3Call(nil), o.mockCalls.DoSomething3...)
+}
+
+// DoSomething3CallCount returns the number of calls to [MockVehicle.DoSomething3].
+func (o *MockVehicle) DoSomething3CallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.DoSomething3)
+}
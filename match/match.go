@@ -0,0 +1,58 @@
+// Package match has the argument matchers for the expectations of mocks generated with --expect, e.g.
+//
+//	mock.ExpectGet(match.Any[context.Context](), match.Eq("key")).Return(item, nil)
+package match
+
+import "fmt"
+
+// Matcher matches the argument of a call to a mock
+type Matcher[T any] interface {
+	// Matches returns true if the argument matches
+	Matches(value T) bool
+	// String describes the values matched, for failure messages, e.g. `"key"` or `any`
+	String() string
+}
+
+// Any matches any value
+func Any[T any]() Matcher[T] {
+	return Func("any", func(T) bool {
+		return true
+	})
+}
+
+// Eq matches values equal to want
+func Eq[T comparable](want T) Matcher[T] {
+	return Func(Format(want), func(value T) bool {
+		return value == want
+	})
+}
+
+// Not matches the values that matcher doesn't
+func Not[T any](matcher Matcher[T]) Matcher[T] {
+	return Func("not "+matcher.String(), func(value T) bool {
+		return !matcher.Matches(value)
+	})
+}
+
+// Func matches the values that matches returns true for. description describes the values for failure messages, e.g. `a positive number`.
+func Func[T any](description string, matches func(value T) bool) Matcher[T] {
+	return funcMatcher[T]{description, matches}
+}
+
+type funcMatcher[T any] struct {
+	description string
+	matches     func(value T) bool
+}
+
+func (matcher funcMatcher[T]) Matches(value T) bool {
+	return matcher.matches(value)
+}
+
+func (matcher funcMatcher[T]) String() string {
+	return matcher.description
+}
+
+// Format formats an argument for failure messages, e.g. `"key"` for a string. It is used by the generated mocks.
+func Format[T any](value T) string {
+	return fmt.Sprintf("%#v", any(value))
+}
@@ -0,0 +1,33 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchers(t *testing.T) {
+	assert.True(t, Any[error]().Matches(nil))
+	assert.Equal(t, "any", Any[int]().String())
+
+	assert.True(t, Eq("key").Matches("key"))
+	assert.False(t, Eq("key").Matches("other key"))
+	assert.Equal(t, `"key"`, Eq("key").String())
+
+	assert.True(t, Not(Eq(3)).Matches(4))
+	assert.False(t, Not(Eq(3)).Matches(3))
+	assert.Equal(t, "not 3", Not(Eq(3)).String())
+
+	positive := Func("a positive number", func(value int) bool {
+		return value > 0
+	})
+	assert.True(t, positive.Matches(1))
+	assert.False(t, positive.Matches(-1))
+	assert.Equal(t, "a positive number", positive.String())
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, `"key"`, Format("key"))
+	assert.Equal(t, "[]string{\"a\", \"b\"}", Format([]string{"a", "b"}))
+	assert.Equal(t, "<nil>", Format[error](nil))
+}
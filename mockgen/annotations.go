@@ -0,0 +1,118 @@
+package mockgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// annotationPrefix is the prefix of the comment directives on interface methods, e.g. `//mockgen:skip`
+const annotationPrefix = "//mockgen:"
+
+// MethodAnnotations tune how a single method is mocked. They are set with comment directives on the method in the interface declaration:
+//
+//	//mockgen:skip                                  the mock has no field for the method, and the method returns the default-return values, or panics
+//	//mockgen:default-return nil, ErrNotImplemented  the values returned when the method's field isn't set, rather than panicking
+//	//mockgen:name CustomField                       the name of the method's field
+type MethodAnnotations struct {
+	Skip bool `json:"skip,omitempty"`
+	// DefaultReturn are the expressions of the values returned when the method's field isn't set, one for each result
+	DefaultReturn []string `json:"defaultReturn,omitempty"`
+	FieldName     string   `json:"fieldName,omitempty"`
+}
+
+// parseMethodAnnotations reads the annotations from the comments on a method with resultsCount results.
+// It also returns the names of the packages referred to by the default-return values, which need to be imported.
+func parseMethodAnnotations(commentGroup *ast.CommentGroup, resultsCount int) (MethodAnnotations, []string, error) {
+	var annotations MethodAnnotations
+	var packageNames []string
+	if commentGroup == nil {
+		return annotations, nil, nil
+	}
+
+	for _, comment := range commentGroup.List {
+		if !strings.HasPrefix(comment.Text, annotationPrefix) {
+			continue
+		}
+
+		directive := strings.TrimPrefix(comment.Text, annotationPrefix)
+		name, args := directive, ""
+		spaceIndex := strings.IndexAny(directive, " \t")
+		if spaceIndex != -1 {
+			name, args = directive[:spaceIndex], strings.TrimSpace(directive[spaceIndex:])
+		}
+
+		switch name {
+		case "skip":
+			if args != "" {
+				return annotations, nil, fmt.Errorf("%sskip doesn't take arguments", annotationPrefix)
+			}
+			annotations.Skip = true
+		case "default-return":
+			values, err := parseExprList(args)
+			if err != nil {
+				return annotations, nil, fmt.Errorf("invalid %sdefault-return values %q: %s", annotationPrefix, args, err)
+			}
+			if len(values) != resultsCount {
+				return annotations, nil, fmt.Errorf("%sdefault-return has %d values, but the method has %d results", annotationPrefix, len(values), resultsCount)
+			}
+			annotations.DefaultReturn = values
+			for _, value := range values {
+				packageNames = append(packageNames, packagesInTypeExpr(value)...)
+			}
+		case "name":
+			if !identifierRegex.MatchString(args) {
+				return annotations, nil, fmt.Errorf("invalid %sname %q, expected a field name", annotationPrefix, args)
+			}
+			annotations.FieldName = args
+		default:
+			return annotations, nil, fmt.Errorf("unknown annotation %s%s", annotationPrefix, name)
+		}
+	}
+
+	return annotations, packageNames, nil
+}
+
+// DefaultReturnValues returns the default-return values as a list, e.g. `nil, ErrNotImplemented`
+func (method Method) DefaultReturnValues() string {
+	return strings.Join(method.Annotations.DefaultReturn, ", ")
+}
+
+// parseExprList parses a comma separated list of expressions, e.g. `nil, errors.New("not implemented")`, and returns them formatted
+func parseExprList(text string) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	// parse the list as the arguments of a call, so that commas inside the expressions are handled
+	expr, err := parser.ParseExpr("f(" + text + ")")
+	if err != nil {
+		if errorList, ok := err.(scanner.ErrorList); ok && len(errorList) != 0 {
+			// leave out the position, which is in the wrapping call rather than the text
+			return nil, errors.New(errorList[0].Msg)
+		}
+		return nil, err
+	}
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok || callExpr.Ellipsis.IsValid() {
+		return nil, fmt.Errorf("not a list of expressions")
+	}
+
+	var values []string
+	for _, arg := range callExpr.Args {
+		buf := bytes.NewBuffer(nil)
+		err = printer.Fprint(buf, token.NewFileSet(), arg)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, buf.String())
+	}
+
+	return values, nil
+}
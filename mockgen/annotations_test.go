@@ -0,0 +1,119 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const annotationsTestSource = `package example
+
+import "errors"
+
+var ErrNotImplemented = errors.New("not implemented")
+
+type Item struct {
+	Name string
+}
+
+type Store interface {
+	// Get gets an item
+	//
+	//mockgen:default-return Item{Name: "default"}, ErrNotImplemented
+	Get(key string) (Item, error)
+	//mockgen:skip
+	Migrate() error
+	//mockgen:skip
+	//mockgen:default-return errors.New("not supported")
+	Compact() error
+	//mockgen:name PutStub
+	Put(key string, item Item) error
+}
+`
+
+func TestGetMethodsForType_annotations(t *testing.T) {
+	typeData, err := GetMethodsForType(annotationsTestSource, "Store")
+	require.NoError(t, err)
+
+	require.Len(t, typeData.Methods, 4)
+	assert.Equal(t, "Get gets an item\n", typeData.Methods[0].Doc)
+	assert.Equal(t, MethodAnnotations{DefaultReturn: []string{`Item{Name: "default"}`, "ErrNotImplemented"}}, typeData.Methods[0].Annotations)
+	assert.Equal(t, MethodAnnotations{Skip: true}, typeData.Methods[1].Annotations)
+	assert.Equal(t, MethodAnnotations{Skip: true, DefaultReturn: []string{`errors.New("not supported")`}}, typeData.Methods[2].Annotations)
+	assert.Equal(t, MethodAnnotations{FieldName: "PutStub"}, typeData.Methods[3].Annotations)
+	require.Len(t, typeData.Imports, 1)
+	assert.Equal(t, `"errors"`, typeData.Imports[0].Path.Value)
+}
+
+func TestGetMethodsForType_invalidAnnotations(t *testing.T) {
+	tests := map[string]string{
+		"//mockgen:default-return nil":    "example.go:5:2: //mockgen:default-return has 1 values, but the method has 2 results",
+		"//mockgen:skip please":           "example.go:5:2: //mockgen:skip doesn't take arguments",
+		"//mockgen:name 2ndField":         `example.go:5:2: invalid //mockgen:name "2ndField", expected a field name`,
+		"//mockgen:unknown":               "example.go:5:2: unknown annotation //mockgen:unknown",
+		"//mockgen:default-return nil, (": `example.go:5:2: invalid //mockgen:default-return values "nil, (": expected operand, found ')'`,
+	}
+	for annotation, expectedErr := range tests {
+		sourceCode := "package example\n\ntype Store interface {\n\t" + annotation + "\n\tGet() (int, error)\n}\n"
+		_, err := getMethodsForType("example.go", sourceCode, "Store")
+		assert.EqualError(t, err, expectedErr, annotation)
+	}
+}
+
+func TestWriteMockType_annotations(t *testing.T) {
+	typeData, err := GetMethodsForType(annotationsTestSource, "Store")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Store", typeData, Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `type MockStore struct {
+	// GetFunc is called by [MockStore.Get].
+	//
+	// Get gets an item
+	GetFunc func(key string) (Item, error)
+	// PutStub is called by [MockStore.Put].
+	PutStub func(key string, item Item) error
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Get []MockStoreGetCall
+		Put []MockStorePutCall
+	}
+}
+`)
+	assert.Contains(t, mockText, `	if o.GetFunc == nil {
+		return Item{Name: "default"}, ErrNotImplemented
+	}
+	return o.GetFunc(key)
+}
+`)
+	assert.Contains(t, mockText, `// Migrate implements [Store.Migrate]. It isn't mocked, and panics when called.
+func (o *MockStore) Migrate() error {
+	panic("Migrate is not mocked")
+}
+
+// Compact implements [Store.Compact] by returning errors.New("not supported"). It isn't mocked.
+func (o *MockStore) Compact() error {
+	return errors.New("not supported")
+}
+`)
+	assert.Contains(t, mockText, `	if o.PutStub == nil {
+		panic("PutStub not defined")
+	}
+	return o.PutStub(key, item)
+}
+`)
+
+	t.Run("out of package", func(t *testing.T) {
+		mockText, err := WriteMockType("Store", typeData, Options{OutPackage: "mocks", PackageImportPath: "example.com/example"})
+		require.NoError(t, err)
+
+		assert.Contains(t, mockText, `		return example.Item{Name: "default"}, example.ErrNotImplemented
+`)
+		assert.Contains(t, mockText, `	return errors.New("not supported")
+`)
+	})
+}
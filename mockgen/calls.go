@@ -0,0 +1,156 @@
+package mockgen
+
+import "fmt"
+
+// CallField is a field of the struct a call to a mocked method is recorded with, holding one of the call's arguments
+type CallField struct {
+	// Name is the field name, the parameter name with its first letter upper cased, e.g. `Err1`
+	Name string
+	// Type is the parameter type. Variadic arguments are recorded as a slice, e.g. `[]string`.
+	Type string
+	// Param is the name of the parameter, e.g. `err1`
+	Param string
+}
+
+// RecordsCalls is true if the mock records the calls to any of its methods. Only the methods with fields record their calls.
+func (mockData *MockData) RecordsCalls() bool {
+	for _, method := range mockData.Methods {
+		if method.Recorded() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Recorded is true if the calls to the method are recorded. Methods skipped with `//mockgen:skip` aren't.
+func (method MockMethod) Recorded() bool {
+	return !method.Annotations.Skip
+}
+
+// CallTypeName is the name of the struct type the calls to the method are recorded with, e.g. `MockVehicleNameCall`,
+// or `MockHandlerCall` for mocks of function types. The type is exported if the mock is.
+func (method MockMethod) CallTypeName() string {
+	if method.Mock.Func {
+		return method.Mock.MockName + "Call"
+	}
+
+	return method.Mock.MockName + upperFirst(method.Name) + "Call"
+}
+
+// CallType is CallTypeName with the type arguments of generic mocks, e.g. `MockCacheGetCall[K, V]`
+func (method MockMethod) CallType() string {
+	return method.CallTypeName() + method.Mock.TypeArgs()
+}
+
+// CallsMethodName is the name of the mock's method returning the recorded calls to the method, e.g. `NameCalls`
+func (method MockMethod) CallsMethodName() string {
+	if method.Mock.Func {
+		return "Calls"
+	}
+
+	return method.Name + "Calls"
+}
+
+// CallCountMethodName is the name of the mock's method returning the number of calls to the method, e.g. `NameCallCount`
+func (method MockMethod) CallCountMethodName() string {
+	if method.Mock.Func {
+		return "CallCount"
+	}
+
+	return method.Name + "CallCount"
+}
+
+// checkCallMethodNames checks that the methods returning the recorded calls don't have the same names as methods of the interface,
+// mocked or delegated
+func checkCallMethodNames(mockData *MockData) error {
+	methodNames := make(map[string]struct{})
+	for _, method := range mockData.TypeData.Methods {
+		methodNames[method.Name] = struct{}{}
+	}
+
+	for _, method := range mockData.Methods {
+		if !method.Recorded() {
+			continue
+		}
+		if _, ok := methodNames[method.CallsMethodName()]; ok {
+			return fmt.Errorf("the mock's %s method, returning the calls to %s, has the same name as a method of the interface", method.CallsMethodName(), method.Name)
+		}
+		if _, ok := methodNames[method.CallCountMethodName()]; ok {
+			return fmt.Errorf("the mock's %s method, returning the number of calls to %s, has the same name as a method of the interface", method.CallCountMethodName(), method.Name)
+		}
+	}
+
+	return nil
+}
+
+// mockMember is a method or field of the mock, with a description for errors
+type mockMember struct {
+	name, description string
+}
+
+// checkFuncAccessorName checks that the method returning a mock of a function type as the function, named after the type,
+// doesn't have the same name as the mock's other methods or its field, e.g. for a function type named `Calls`
+func checkFuncAccessorName(mockData *MockData) error {
+	if !mockData.Func {
+		return nil
+	}
+
+	var members []mockMember
+	for _, method := range mockData.Methods {
+		members = append(members,
+			mockMember{method.Name, "the method called with the function's arguments"},
+			mockMember{method.FieldName, "the field setting its behaviour"},
+		)
+		if method.Recorded() {
+			members = append(members,
+				mockMember{method.CallsMethodName(), "the method returning the recorded calls"},
+				mockMember{method.CallCountMethodName(), "the method returning the number of calls"},
+			)
+		}
+		if method.HasReturns() {
+			for _, suffix := range []string{"", "OnCall", "Sequence"} {
+				members = append(members, mockMember{method.ReturnsMethodName() + suffix, "a method setting the results"})
+			}
+		}
+		if mockData.RecordsOrder() && method.Recorded() {
+			members = append(members,
+				mockMember{recordOrderMethodName, "the method recording the order of the calls"},
+				mockMember{method.CalledMethodName(), "the method returning the step of a call"},
+			)
+		}
+		if mockData.Expectations && method.Recorded() {
+			members = append(members, mockMember{method.ExpectMethodName(), "the method adding an expected call"})
+		}
+		if mockData.Synchronized {
+			members = append(members, mockMember{method.SetterName(), "the method setting " + method.FieldName})
+		}
+	}
+
+	for _, member := range members {
+		if member.name == mockData.InterfaceName {
+			return fmt.Errorf("the mock's %s method, returning the mock as a %s, has the same name as %s", mockData.InterfaceName, mockData.QualifiedInterfaceName, member.description)
+		}
+	}
+
+	return nil
+}
+
+// CallFields are the fields of the struct the calls to the method are recorded with, one for each parameter
+func (method MockMethod) CallFields() []CallField {
+	var fields []CallField
+	for i, paramName := range method.ParamNames() {
+		param := method.Params[i]
+		fieldType := param.FullTypeName()
+		if param.Variadic {
+			fieldType = "[]" + fieldType
+		}
+		fields = append(fields, CallField{
+			Name:  upperFirst(paramName),
+			Type:  fieldType,
+			Param: paramName,
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,20 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMockType_allMethodsSkipped(t *testing.T) {
+	typeData, err := GetMethodsForType("package example\ntype Vehicle interface {\n\t//mockgen:skip\n\tReset()\n}\n", "Vehicle")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Vehicle", typeData, Options{})
+	require.NoError(t, err)
+
+	// nothing is recorded, so the mock has no mutex
+	assert.NotContains(t, mockText, "mockMu")
+	assert.NotContains(t, mockText, `"sync"`)
+}
@@ -0,0 +1,142 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CompositeTypeData combines several interfaces into one, so that a single mock implements all of them.
+// The interfaces are either declared in the package in dirPath, e.g. `Store`, or given with the import path of their package,
+// e.g. `io.Closer` or `example.com/health.Checker`, which is found with the resolver.
+//
+// The methods are the union of the interfaces' methods. Methods in several of the interfaces must have the same signature.
+func CompositeTypeData(dirPath string, interfaceNames []string, resolver *Resolver) (*TypeData, error) {
+	packageName, err := packageNameInDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	typeData := &TypeData{
+		PackageName: packageName,
+	}
+
+	// importPaths are the import paths of the packages of the interfaces, by package name
+	importPaths := make(map[string]string)
+	for _, interfaceName := range interfaceNames {
+		dotIndex := strings.LastIndex(interfaceName, ".")
+		if dotIndex == -1 {
+			if !identifierRegex.MatchString(interfaceName) {
+				return nil, fmt.Errorf("invalid interface name %q", interfaceName)
+			}
+			typeData.Interfaces = appendIfMissing(typeData.Interfaces, interfaceName)
+			continue
+		}
+
+		importPath, name := interfaceName[:dotIndex], interfaceName[dotIndex+1:]
+		if importPath == "" || !identifierRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid interface name %q, expected a name such as Store or io.Closer", interfaceName)
+		}
+
+		packageDirPath, err := resolver.DirForImportPath(importPath)
+		if err != nil {
+			return nil, err
+		}
+		interfacePackageName, err := packageNameInDir(packageDirPath)
+		if err != nil {
+			return nil, err
+		}
+		if existingImportPath, ok := importPaths[interfacePackageName]; ok && existingImportPath != importPath {
+			return nil, fmt.Errorf("packages %s and %s are both named %s", existingImportPath, importPath, interfacePackageName)
+		}
+
+		if _, ok := importPaths[interfacePackageName]; !ok {
+			importPaths[interfacePackageName] = importPath
+			importSpec := &ast.ImportSpec{
+				Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(importPath)},
+			}
+			if path.Base(importPath) != interfacePackageName {
+				importSpec.Name = ast.NewIdent(interfacePackageName)
+			}
+			typeData.Imports = append(typeData.Imports, importSpec)
+		}
+
+		typeData.Interfaces = appendIfMissing(typeData.Interfaces, interfacePackageName+"."+name)
+	}
+
+	if len(typeData.Interfaces) < 2 {
+		return nil, fmt.Errorf("at least two interfaces are needed for a composite mock, got %q", strings.Join(interfaceNames, ","))
+	}
+
+	typeData.EmbeddedInterfaces = append([]string(nil), typeData.Interfaces...)
+	typeData.Literal = "interface{ " + strings.Join(typeData.Interfaces, "; ") + " }"
+
+	err = FlattenEmbeddedInterfaces(typeData, dirPath, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	return typeData, nil
+}
+
+// InterfaceDocLinks returns doc links to the interfaces the mock implements, e.g. `[Vehicle]`, or `[Store], [io.Closer] and [Health]` for composite mocks.
+// Anonymous interfaces have no declaration to link to, so they are described in plain text.
+func (mockData *MockData) InterfaceDocLinks() string {
+	if mockData.anonymous() {
+		return "an anonymous interface"
+	}
+	if len(mockData.Interfaces) == 0 {
+		return "[" + mockData.QualifiedInterfaceName + "]"
+	}
+
+	var links []string
+	for _, interfaceName := range mockData.Interfaces {
+		links = append(links, "["+interfaceName+"]")
+	}
+
+	return strings.Join(links[:len(links)-1], ", ") + " and " + links[len(links)-1]
+}
+
+// anonymous is true if the mock implements an anonymous interface, rather than declared ones
+func (mockData *MockData) anonymous() bool {
+	return mockData.Literal != "" && len(mockData.Interfaces) == 0
+}
+
+// signature returns the types of the method's parameters and results, without the parameter names, e.g. `(string, ...int) (Item, error)`
+func (method Method) signature() string {
+	var paramTypeNames []string
+	for _, param := range method.Params {
+		paramTypeNames = append(paramTypeNames, param.paramTypeName())
+	}
+
+	return strings.TrimSpace("(" + strings.Join(paramTypeNames, ", ") + ") " + method.ReturnTypesAsString())
+}
+
+// packageNameInDir returns the name of the package in the directory, from the package clause of its first Go file that isn't a test
+func packageNameInDir(dirPath string) (string, error) {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading directory: %q", err)
+	}
+
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), ".go") || strings.HasSuffix(fileInfo.Name(), "_test.go") {
+			continue
+		}
+
+		parsedFile, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dirPath, fileInfo.Name()), nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+
+		return parsedFile.Name.Name, nil
+	}
+
+	return "", fmt.Errorf("no Go files found in %s", dirPath)
+}
@@ -0,0 +1,99 @@
+package mockgen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeTypeData(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-composite-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	writeTestFiles(t, dirPath, map[string]string{
+		"go.work":  "go 1.21\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.21\n\nrequire example.com/b v0.0.0\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.21\n",
+		"a/store/store.go": `package store
+
+type Item struct{}
+
+type Store interface {
+	Get(key string) (Item, error)
+	Close() error
+}
+
+type BadStore interface {
+	Close() bool
+}
+`,
+		"b/health/health.go": `package healthcheck
+
+type Status int
+
+type Checker interface {
+	Check() Status
+}
+`,
+	})
+
+	storeDirPath := filepath.Join(dirPath, "a", "store")
+	resolver, err := NewResolver(storeDirPath)
+	require.NoError(t, err)
+
+	typeData, err := CompositeTypeData(storeDirPath, []string{"Store", "io.Closer", "example.com/b/health.Checker"}, resolver)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Store", "io.Closer", "healthcheck.Checker"}, typeData.Interfaces)
+
+	mockText, err := WriteMockType("MockStoreCloser", typeData, Options{Naming: Naming{MockName: "MockStoreCloser"}})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `import (
+	healthcheck "example.com/b/health"
+	"io"
+	"sync"
+)
+
+// MockStoreCloser is a mock implementation of [Store], [io.Closer] and [healthcheck.Checker].
+type MockStoreCloser struct {
+	// GetFunc is called by [MockStoreCloser.Get].
+	GetFunc func(key string) (Item, error)
+	// CloseFunc is called by [MockStoreCloser.Close].
+	CloseFunc func() error
+	// CheckFunc is called by [MockStoreCloser.Check].
+	CheckFunc func() healthcheck.Status
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Get   []MockStoreCloserGetCall
+		Close []MockStoreCloserCloseCall
+		Check []MockStoreCloserCheckCall
+	}
+}
+
+var (
+	// compile time checks that *MockStoreCloser implements the interface and the interfaces it embeds
+	_ Store               = &MockStoreCloser{}
+	_ io.Closer           = &MockStoreCloser{}
+	_ healthcheck.Checker = &MockStoreCloser{}
+)
+`)
+	assert.Contains(t, mockText, "// Check implements [healthcheck.Checker.Check] by calling CheckFunc.")
+
+	t.Run("conflicting methods", func(t *testing.T) {
+		_, err := CompositeTypeData(storeDirPath, []string{"BadStore", "io.Closer"}, resolver)
+		assert.EqualError(t, err, "method Close() error of io.Closer conflicts with method Close() bool of BadStore")
+	})
+
+	t.Run("single interface", func(t *testing.T) {
+		_, err := CompositeTypeData(storeDirPath, []string{"Store", "Store"}, resolver)
+		assert.Error(t, err)
+	})
+}
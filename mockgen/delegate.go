@@ -0,0 +1,53 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+)
+
+// selectMethods returns the methods to be mocked, with options.Methods or options.ExcludeMethods.
+// The other methods are delegated to an implementation of the interface embedded in the mock.
+// delegated is true if some of the methods are delegated.
+func selectMethods(methods []MockMethod, options Options) (selectedMethods []MockMethod, delegated bool, err error) {
+	if len(options.Methods) == 0 && len(options.ExcludeMethods) == 0 {
+		return methods, false, nil
+	}
+	if len(options.Methods) != 0 && len(options.ExcludeMethods) != 0 {
+		return nil, false, errors.New("only one of the methods to mock and the methods to exclude can be given")
+	}
+
+	methodNames := make(map[string]struct{})
+	for _, method := range methods {
+		methodNames[method.Name] = struct{}{}
+	}
+	for _, methodName := range append(options.Methods, options.ExcludeMethods...) {
+		if _, ok := methodNames[methodName]; !ok {
+			return nil, false, fmt.Errorf("method %s not found in the interface", methodName)
+		}
+	}
+
+	isMocked := func(methodName string) bool {
+		if len(options.Methods) != 0 {
+			return containsString(options.Methods, methodName)
+		}
+		return !containsString(options.ExcludeMethods, methodName)
+	}
+
+	for _, method := range methods {
+		if isMocked(method.Name) {
+			selectedMethods = append(selectedMethods, method)
+		}
+	}
+
+	return selectedMethods, true, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, existingValue := range values {
+		if existingValue == value {
+			return true
+		}
+	}
+
+	return false
+}
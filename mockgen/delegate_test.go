@@ -0,0 +1,92 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delegateTestSource = `package example
+
+import "io"
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+	Delete(key string) error
+	io.Closer
+}
+`
+
+func TestWriteMockType_methods(t *testing.T) {
+	typeData, err := GetMethodsForType(delegateTestSource, "Store")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Store", typeData, Options{Methods: []string{"Get", "Put"}})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `type MockStore struct {
+	// Store is the implementation that the methods without fields are delegated to
+	Store
+	// GetFunc is called by [MockStore.Get].
+	GetFunc func(key string) (string, error)
+	// PutFunc is called by [MockStore.Put].
+	PutFunc func(key string, value string) error
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Get []MockStoreGetCall
+		Put []MockStorePutCall
+	}
+}
+`)
+	assert.Contains(t, mockText, `// NewMockStore creates a mock that delegates the methods without fields to delegate.
+func NewMockStore(delegate Store) *MockStore {
+	return &MockStore{Store: delegate}
+}
+`)
+	assert.NotContains(t, mockText, "DeleteFunc")
+
+	t.Run("exclude", func(t *testing.T) {
+		mockText, err := WriteMockType("Store", typeData, Options{ExcludeMethods: []string{"Delete"}})
+		require.NoError(t, err)
+
+		assert.Contains(t, mockText, "GetFunc func")
+		assert.Contains(t, mockText, "PutFunc func")
+		assert.NotContains(t, mockText, "DeleteFunc")
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, err := WriteMockType("Store", typeData, Options{Methods: []string{"Gett"}})
+		assert.EqualError(t, err, "method Gett not found in the interface")
+	})
+
+	t.Run("imports of delegated methods", func(t *testing.T) {
+		typeData, err := GetMethodsForType(`package example
+
+import (
+	"context"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+}
+`, "Clock")
+		require.NoError(t, err)
+
+		mockText, err := WriteMockType("Clock", typeData, Options{Methods: []string{"Now"}})
+		require.NoError(t, err)
+
+		assert.Contains(t, mockText, `"time"`)
+		assert.NotContains(t, mockText, `"context"`)
+	})
+
+	t.Run("methods and exclude", func(t *testing.T) {
+		_, err := WriteMockType("Store", typeData, Options{Methods: []string{"Get"}, ExcludeMethods: []string{"Put"}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// matchPackagePath is the import path of the package with the argument matchers used by expectations, see Options.Expectations
+const matchPackagePath = "github.com/jamesrr39/go-mockgen-tool/match"
+
+// ExpectationTypeName is the name of the type of the expected calls to the method, e.g. `MockVehicleNameExpectation`,
+// or `MockHandlerExpectation` for mocks of function types
+func (method MockMethod) ExpectationTypeName() string {
+	if method.Mock.Func {
+		return method.Mock.MockName + "Expectation"
+	}
+
+	return method.Mock.MockName + upperFirst(method.Name) + "Expectation"
+}
+
+// ExpectationType is ExpectationTypeName with the type arguments of generic mocks, e.g. `MockCacheGetExpectation[K, V]`
+func (method MockMethod) ExpectationType() string {
+	return method.ExpectationTypeName() + method.Mock.TypeArgs()
+}
+
+// ExpectMethodName is the name of the mock's method adding an expected call to the method, e.g. `ExpectName`, or `Expect` for mocks of function types.
+// It is unexported if the method is, e.g. `expectTest2`.
+func (method MockMethod) ExpectMethodName() string {
+	if method.Mock.Func {
+		return "Expect"
+	}
+	if !token.IsExported(method.Name) {
+		return "expect" + upperFirst(method.Name)
+	}
+
+	return "Expect" + method.Name
+}
+
+// MatcherParams are the parameters of the method adding an expected call, one matcher for each parameter of the method,
+// e.g. `key match.Matcher[string], tags match.Matcher[[]string]`
+func (method MockMethod) MatcherParams() string {
+	var params []string
+	for _, field := range method.CallFields() {
+		params = append(params, fmt.Sprintf("%s match.Matcher[%s]", field.Param, field.Type))
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// ResultParams are the parameters of the Return method of an expected call, one for each result, e.g. `result0 int, result1 error`
+func (method MockMethod) ResultParams() string {
+	var params []string
+	for i, returnType := range method.ReturnTypes {
+		params = append(params, fmt.Sprintf("result%d %s", i, returnType.FullTypeName()))
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// ResultArgs are the names of the parameters of ResultParams, e.g. `result0, result1`
+func (method MockMethod) ResultArgs() string {
+	var args []string
+	for i := range method.ReturnTypes {
+		args = append(args, fmt.Sprintf("result%d", i))
+	}
+
+	return strings.Join(args, ", ")
+}
+
+// CallArgsFromCall are the arguments of a call recorded in a variable named call, for forwarding the call, e.g. `call.Key, call.Tags...`
+func (method MockMethod) CallArgsFromCall() string {
+	var args []string
+	for i, field := range method.CallFields() {
+		arg := "call." + field.Name
+		if method.Params[i].Variadic {
+			arg += "..."
+		}
+		args = append(args, arg)
+	}
+
+	return strings.Join(args, ", ")
+}
+
+// checkExpectations checks that the mock can have expectations
+func checkExpectations(mockData *MockData, version goVersion) error {
+	if version.isKnown() && version < goVersionGenerics {
+		return fmt.Errorf("expectations use generics, which need Go %s or later, but the mock is generated for Go %s. Set the Go version with the go directive in go.mod or --go-version", goVersion(goVersionGenerics), version)
+	}
+
+	methodNames := make(map[string]struct{})
+	for _, method := range mockData.Methods {
+		methodNames[method.Name] = struct{}{}
+	}
+	for _, method := range mockData.Methods {
+		if _, ok := methodNames[method.ExpectMethodName()]; ok && method.Recorded() {
+			return fmt.Errorf("the mock's %s method, adding expected calls to %s, has the same name as a method of the interface", method.ExpectMethodName(), method.Name)
+		}
+	}
+
+	for _, im := range mockData.Imports {
+		name := im.Name
+		if name == "" {
+			name = path.Base(im.Path)
+		}
+		if name == "match" && im.Path != matchPackagePath {
+			return fmt.Errorf("the mock uses package %s, which has the same name as the package of the matchers used by expectations, %s", im.Path, matchPackagePath)
+		}
+	}
+
+	return nil
+}
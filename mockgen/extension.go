@@ -0,0 +1,93 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+	"sync"
+)
+
+// Extension contributes extra code to generated mocks, e.g. extra methods, fields or whole files per interface.
+//
+// Extensions are passed in with Options.Extensions. To use extensions with the command line tool,
+// write a main package that registers them with RegisterExtension and then calls cli.Main().
+type Extension interface {
+	// Name identifies the extension in error messages
+	Name() string
+	// Extend is called once per generated mock, with the resolved interface. It should not modify mockData.
+	Extend(mockData *MockData) (*Contribution, error)
+}
+
+// Contribution is the code an extension adds to a mock. The fragments are merged into the mock file and formatted with gofmt.
+type Contribution struct {
+	// Imports are the packages the contributed code needs. They are merged with the mock's imports.
+	Imports []Import
+	// Fields are added to the mock struct, e.g. `callCount int`
+	Fields []string
+	// Decls are top-level declarations (functions, methods, types, variables) appended to the mock file
+	Decls []string
+	// Files are extra files to be written next to the mock file
+	Files []File
+}
+
+// File is a generated file. Go files are formatted with gofmt.
+type File struct {
+	// Name is the file name, relative to the directory of the mock file
+	Name    string
+	Content string
+}
+
+var (
+	registeredExtensionsMu sync.Mutex
+	registeredExtensions   []Extension
+)
+
+// RegisterExtension registers an extension to be used by the command line tool (see the cli package).
+// It is intended to be called from a custom main package, or the init function of a package imported by it.
+func RegisterExtension(extension Extension) {
+	registeredExtensionsMu.Lock()
+	defer registeredExtensionsMu.Unlock()
+
+	registeredExtensions = append(registeredExtensions, extension)
+}
+
+// RegisteredExtensions returns the extensions registered with RegisterExtension, in the order they were registered
+func RegisteredExtensions() []Extension {
+	registeredExtensionsMu.Lock()
+	defer registeredExtensionsMu.Unlock()
+
+	return append([]Extension(nil), registeredExtensions...)
+}
+
+// applyExtensions runs the extensions and merges their contributions into mockData. Extra files contributed are returned.
+func applyExtensions(mockData *MockData, extensions []Extension) ([]File, error) {
+	var files []File
+	for _, extension := range extensions {
+		contribution, err := extension.Extend(mockData)
+		if err != nil {
+			return nil, fmt.Errorf("extension %q failed: %s", extension.Name(), err)
+		}
+		if contribution == nil {
+			continue
+		}
+
+		for _, im := range contribution.Imports {
+			mockData.addImport(im)
+		}
+		mockData.ExtraFields = append(mockData.ExtraFields, contribution.Fields...)
+		mockData.ExtraDecls = append(mockData.ExtraDecls, contribution.Decls...)
+
+		for _, file := range contribution.Files {
+			if strings.HasSuffix(file.Name, ".go") {
+				formatted, err := format.Source([]byte(file.Content))
+				if err != nil {
+					return nil, fmt.Errorf("extension %q generated invalid Go for file %q: %s", extension.Name(), file.Name, err)
+				}
+				file.Content = string(formatted)
+			}
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,68 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingExtension struct{}
+
+func (countingExtension) Name() string {
+	return "counting"
+}
+
+func (countingExtension) Extend(mockData *MockData) (*Contribution, error) {
+	return &Contribution{
+		Imports: []Import{{Path: "sync/atomic"}, {Path: "io"}},
+		Fields:  []string{"calls int64"},
+		Decls: []string{
+			fmt.Sprintf("func (o *%s) incrementCalls() { atomic.AddInt64(&o.calls, 1) }", mockData.MockName),
+		},
+		Files: []File{{
+			Name:    "registry_mock.go",
+			Content: fmt.Sprintf("package %s\nvar registry = map[string]interface{}{%q: &%s{}}", mockData.PackageName, mockData.InterfaceName, mockData.MockName),
+		}},
+	}, nil
+}
+
+type failingExtension struct{}
+
+func (failingExtension) Name() string {
+	return "failing"
+}
+
+func (failingExtension) Extend(mockData *MockData) (*Contribution, error) {
+	return nil, errors.New("something went wrong")
+}
+
+func TestGenerateMock_extensions(t *testing.T) {
+	typeData, err := GetMethodsForType(templateTestSource, "Logger")
+	require.NoError(t, err)
+
+	generatedMock, err := GenerateMock("Logger", typeData, Options{Extensions: []Extension{countingExtension{}}})
+	require.NoError(t, err)
+
+	assert.Contains(t, generatedMock.Text, `import (
+	"io"
+	"sync"
+	"sync/atomic"
+)`)
+	assert.Contains(t, generatedMock.Text, `	io.Closer
+	calls int64
+
+	mockMu sync.Mutex`)
+	assert.Contains(t, generatedMock.Text, `func (o *MockLogger) incrementCalls() { atomic.AddInt64(&o.calls, 1) }`)
+
+	require.Len(t, generatedMock.ExtraFiles, 1)
+	assert.Equal(t, File{
+		Name:    "registry_mock.go",
+		Content: "package example\n\nvar registry = map[string]interface{}{\"Logger\": &MockLogger{}}\n",
+	}, generatedMock.ExtraFiles[0])
+
+	_, err = GenerateMock("Logger", typeData, Options{Extensions: []Extension{failingExtension{}}})
+	require.EqualError(t, err, `extension "failing" failed: something went wrong`)
+}
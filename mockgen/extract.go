@@ -0,0 +1,683 @@
+package mockgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExtractOptions configures the interface generated from a concrete type by ExtractInterface
+type ExtractOptions struct {
+	// InterfaceName is the name of the generated interface. Defaults to the name of the type, if it is declared in another package.
+	InterfaceName string
+	// Methods are the names of the only methods to include in the interface, and ExcludeMethods the names of the methods to leave out.
+	// Only one of them can be given.
+	Methods, ExcludeMethods []string
+	// Header is text, e.g. a license, added to the top of the file
+	Header string
+	// OnlyUsed limits the interface to the methods that the package in the directory calls on the type, see UsedMethods.
+	// Methods can't be given with it, ExcludeMethods can.
+	OnlyUsed bool
+}
+
+// ExtractedInterface is an interface generated from the method set of a concrete type
+type ExtractedInterface struct {
+	// Name is the name of the interface
+	Name string
+	// Text is the content of a file declaring the interface, in the package in the directory passed to ExtractInterface
+	Text string
+}
+
+// ExtractInterface generates an interface with the exported method set of a concrete type, e.g. to mock a client from another module.
+//
+// The type is either declared in the package in dirPath, e.g. `*Store`, or in another package, e.g. `*pgstore.Store`. The package of
+// the type is referred to by the name it is imported with in dirPath, or by its import path, e.g. `*github.com/acme/pgstore.Store`,
+// and is found with the resolver. As in Go, the method set of `*T` includes the methods with pointer receivers, and the method set of `T` doesn't.
+// Methods promoted from embedded fields are included.
+func ExtractInterface(dirPath, typeExpr string, options ExtractOptions, resolver *Resolver) (*ExtractedInterface, error) {
+	if len(options.Methods) != 0 && len(options.ExcludeMethods) != 0 {
+		return nil, errors.New("only one of the methods to include and the methods to exclude can be given")
+	}
+
+	pointer, qualifier, typeName, err := parseConcreteType(typeExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	dirPath, err = filepath.Abs(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPackage, err := loadGoPackage(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor := &interfaceExtractor{
+		resolver:      resolver,
+		targetPackage: targetPackage,
+		imports:       make(map[string]string),
+		methodNames:   make(map[string]struct{}),
+	}
+
+	typePackage, typeQualifier := targetPackage, ""
+	if qualifier != "" {
+		importPath := targetPackage.importPathForName(qualifier)
+		if importPath == "" {
+			// not imported by the package, so the qualifier is the import path
+			importPath = qualifier
+		}
+		typePackage, typeQualifier, err = extractor.loadImportedPackage(importPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	interfaceName := options.InterfaceName
+	if interfaceName == "" {
+		if typeQualifier == "" {
+			return nil, fmt.Errorf("a name is needed for the interface, as %s is declared in the same package", typeName)
+		}
+		interfaceName = typeName
+	}
+	if !identifierRegex.MatchString(interfaceName) {
+		return nil, fmt.Errorf("invalid interface name %q", interfaceName)
+	}
+	if typeQualifier == "" && interfaceName == typeName {
+		return nil, fmt.Errorf("the interface can't have the same name as %s, which is in the same package", typeName)
+	}
+
+	if options.OnlyUsed {
+		if len(options.Methods) != 0 {
+			return nil, errors.New("the methods to include can't be given along with only including the used methods")
+		}
+		usedMethods, err := UsedMethods(dirPath, typeExpr, interfaceName, resolver)
+		if err != nil {
+			return nil, err
+		}
+		for _, methodName := range usedMethods {
+			if !containsString(options.ExcludeMethods, methodName) {
+				options.Methods = append(options.Methods, methodName)
+			}
+		}
+		if len(options.Methods) == 0 {
+			return nil, fmt.Errorf("no methods of %s are called in %s", typeExpr, dirPath)
+		}
+		options.ExcludeMethods = nil
+	}
+
+	err = extractor.addMethodSet(typePackage, typeQualifier, typeName, pointer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = extractor.filterMethods(options)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := fmt.Sprintf("%s is the interface of the exported methods of %s.", interfaceName, typeExpr)
+	if options.OnlyUsed {
+		doc = fmt.Sprintf("%s is the interface of the methods of %s used in package %s.", interfaceName, typeExpr, targetPackage.name)
+	} else if len(options.Methods) != 0 || len(options.ExcludeMethods) != 0 {
+		doc = fmt.Sprintf("%s is the interface of some of the exported methods of %s.", interfaceName, typeExpr)
+	}
+
+	text, err := extractor.render(interfaceName, doc, options.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtractedInterface{
+		Name: interfaceName,
+		Text: text,
+	}, nil
+}
+
+// parseConcreteType parses a type such as `*pgstore.Store`, returning whether it is a pointer type, the package qualifier
+// (a package name or an import path, empty for types in the same package) and the type name
+func parseConcreteType(typeExpr string) (pointer bool, qualifier, typeName string, err error) {
+	pointer = strings.HasPrefix(typeExpr, "*")
+	typeRef := strings.TrimPrefix(typeExpr, "*")
+	typeName = typeRef
+	dotIndex := strings.LastIndex(typeRef, ".")
+	if dotIndex != -1 {
+		qualifier, typeName = typeRef[:dotIndex], typeRef[dotIndex+1:]
+	}
+	if !identifierRegex.MatchString(typeName) || (dotIndex != -1 && qualifier == "") {
+		return false, "", "", fmt.Errorf("invalid type %q, expected a type such as *Store or *pgstore.Store", typeExpr)
+	}
+
+	return pointer, qualifier, typeName, nil
+}
+
+// interfaceExtractor collects the methods of a concrete type for ExtractInterface
+type interfaceExtractor struct {
+	resolver *Resolver
+	// targetPackage is the package the interface is generated in
+	targetPackage *goPackage
+	// imports are the import paths of the packages the interface refers to, by package name
+	imports map[string]string
+	// elements are the methods, e.g. `Get(key string) (Item, error)`, and embedded interfaces of the interface, with their doc comments
+	elements    []interfaceElement
+	methodNames map[string]struct{}
+}
+
+type interfaceElement struct {
+	// Name is the method name, or empty for embedded interfaces
+	Name string
+	Doc  string
+	// Text is the method name and signature, or the embedded interface
+	Text string
+	// Methods are the methods of an embedded interface, if they are known, which are added instead of the interface
+	// if only some of them are included. Imports are the import paths of the packages they refer to, by package name.
+	Methods []interfaceElement
+	Imports map[string]string
+}
+
+// addMethodSet adds the exported methods of the named type in the package to the interface, along with the ones promoted from its embedded fields.
+// qualifier is the name the package is referred to by from the interface's package, empty for the interface's package itself.
+// The methods with pointer receivers are only added if addressable is true, i.e. for the method set of a pointer to the type.
+// seen are the types already visited through embedded fields, to stop at cycles.
+func (extractor *interfaceExtractor) addMethodSet(pkg *goPackage, qualifier, typeName string, addressable bool, seen []string) error {
+	typeKey := pkg.dirPath + "." + typeName
+	if containsString(seen, typeKey) {
+		return nil
+	}
+	seen = append(seen, typeKey)
+
+	typeSpec, file := pkg.findTypeSpec(typeName)
+	if typeSpec == nil {
+		return fmt.Errorf("type %s not found in %s", typeName, pkg.dirPath)
+	}
+	if typeSpec.TypeParams != nil {
+		return fmt.Errorf("%s is generic, which isn't supported", typeName)
+	}
+	if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+		return fmt.Errorf("%s is an interface, rather than a concrete type", typeName)
+	}
+
+	for _, methodFile := range pkg.files {
+		for _, decl := range methodFile.file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || !funcDecl.Name.IsExported() {
+				continue
+			}
+			receiverTypeName, pointerReceiver := receiverType(funcDecl)
+			if receiverTypeName != typeName || (pointerReceiver && !addressable) {
+				continue
+			}
+			err := extractor.addMethod(methodFile, qualifier, funcDecl)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+
+	// methods promoted from embedded fields. Methods of the type take precedence, as they are added first.
+	for _, field := range structType.Fields.List {
+		if len(field.Names) != 0 {
+			continue
+		}
+		err := extractor.addEmbeddedField(pkg, file, qualifier, field.Type, addressable, seen)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addEmbeddedField adds the methods promoted from an embedded field of a struct type declared in the file
+func (extractor *interfaceExtractor) addEmbeddedField(pkg *goPackage, file *goFile, qualifier string, fieldType ast.Expr, addressable bool, seen []string) error {
+	if starExpr, ok := fieldType.(*ast.StarExpr); ok {
+		// all the methods of *T are promoted from an embedded *T
+		fieldType, addressable = starExpr.X, true
+	}
+
+	embeddedPackage, embeddedQualifier := pkg, qualifier
+	var embeddedTypeName string
+	switch t := fieldType.(type) {
+	case *ast.Ident:
+		embeddedTypeName = t.Name
+	case *ast.SelectorExpr:
+		packageIdent, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		importPath := file.importPathForName(packageIdent.Name)
+		if importPath == "" {
+			return fmt.Errorf("no import found for package %s in %s", packageIdent.Name, file.path)
+		}
+		var err error
+		embeddedPackage, embeddedQualifier, err = extractor.loadImportedPackage(importPath)
+		if err != nil {
+			return err
+		}
+		embeddedTypeName = t.Sel.Name
+	default:
+		// e.g. instantiated generic types
+		return nil
+	}
+
+	typeSpec, _ := embeddedPackage.findTypeSpec(embeddedTypeName)
+	if typeSpec == nil {
+		// e.g. predeclared types, such as an embedded error
+		if embeddedTypeName == "error" && embeddedPackage == pkg {
+			extractor.addElement(interfaceElement{
+				Text:    "error",
+				Methods: []interfaceElement{{Name: "Error", Text: "Error() string"}},
+			})
+		}
+		return nil
+	}
+
+	if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+		// an embedded interface promotes all its methods, so the generated interface embeds it too
+		if embeddedQualifier != "" && !ast.IsExported(embeddedTypeName) {
+			return fmt.Errorf("embedded interface %s.%s is unexported, so it can't be used outside package %s", embeddedQualifier, embeddedTypeName, embeddedPackage.name)
+		}
+		interfaceText := embeddedTypeName
+		if embeddedQualifier != "" {
+			interfaceText = embeddedQualifier + "." + embeddedTypeName
+		}
+		element, err := extractor.embeddedInterfaceElement(embeddedPackage, embeddedQualifier, embeddedTypeName)
+		if err != nil {
+			return fmt.Errorf("embedded interface %s: %s", interfaceText, err)
+		}
+		element.Text = interfaceText
+		extractor.addElement(element)
+		return nil
+	}
+
+	return extractor.addMethodSet(embeddedPackage, embeddedQualifier, embeddedTypeName, addressable, seen)
+}
+
+// embeddedInterfaceElement loads the methods of an interface embedded in a struct
+func (extractor *interfaceExtractor) embeddedInterfaceElement(pkg *goPackage, qualifier, interfaceName string) (interfaceElement, error) {
+	element := interfaceElement{Imports: make(map[string]string)}
+
+	typeData, err := GetMethodsForTypeInDir(pkg.dirPath, interfaceName)
+	if err != nil {
+		return element, err
+	}
+	err = FlattenEmbeddedInterfaces(typeData, pkg.dirPath, extractor.resolver)
+	if err != nil {
+		return element, err
+	}
+	if len(typeData.EmbeddedInterfaces) != 0 {
+		// not all the methods are known, so the interface can only be embedded as a whole
+		return element, nil
+	}
+	if qualifier != "" {
+		typeData, err = qualifyTypeDataWith(typeData, qualifier)
+		if err != nil {
+			return element, err
+		}
+	}
+
+	for _, im := range typeData.Imports {
+		importPath, err := strconv.Unquote(im.Path.Value)
+		if err != nil {
+			return element, err
+		}
+		element.Imports[importShortName(im)] = importPath
+	}
+	for _, method := range typeData.Methods {
+		element.Methods = append(element.Methods, interfaceElement{
+			Name: method.Name,
+			Doc:  method.Doc,
+			Text: fmt.Sprintf("%s(%s) %s", method.Name, method.ParamsWithTypes(), method.ReturnTypesAsString()),
+		})
+	}
+
+	return element, nil
+}
+
+// addMethod adds a method declared in the file to the interface, unless a method with the same name has already been added
+func (extractor *interfaceExtractor) addMethod(file *goFile, qualifier string, funcDecl *ast.FuncDecl) error {
+	methodName := funcDecl.Name.Name
+	if _, ok := extractor.methodNames[methodName]; ok {
+		return nil
+	}
+
+	// the signature as a function type, e.g. `func(key string) (Item, error)`, to qualify it as a whole
+	signature := "func" + file.nodeSource(funcDecl.Type.Params)
+	if funcDecl.Type.Results != nil {
+		signature += " " + file.nodeSource(funcDecl.Type.Results)
+	}
+
+	for _, packageName := range packagesInTypeExpr(signature) {
+		importPath := file.importPathForName(packageName)
+		if importPath == "" {
+			return fmt.Errorf("method %s: no import found for package %s in %s", methodName, packageName, file.path)
+		}
+		err := extractor.addImport(packageName, importPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if qualifier != "" {
+		var err error
+		signature, err = qualifyTypeExpr(signature, qualifier, nil)
+		if err != nil {
+			return fmt.Errorf("method %s: %s. Leave it out of the interface with the methods to exclude", methodName, err)
+		}
+	}
+
+	extractor.methodNames[methodName] = struct{}{}
+	extractor.addElement(interfaceElement{
+		Name: methodName,
+		Doc:  funcDecl.Doc.Text(),
+		Text: methodName + strings.TrimPrefix(signature, "func"),
+	})
+
+	return nil
+}
+
+func (extractor *interfaceExtractor) addElement(element interfaceElement) {
+	for _, existingElement := range extractor.elements {
+		if existingElement.Name == "" && existingElement.Text == element.Text {
+			return
+		}
+	}
+
+	extractor.elements = append(extractor.elements, element)
+}
+
+// addImport records that the interface refers to the package, returning an error if another package has the same name
+func (extractor *interfaceExtractor) addImport(packageName, importPath string) error {
+	existingImportPath, ok := extractor.imports[packageName]
+	if ok && existingImportPath != importPath {
+		return fmt.Errorf("packages %s and %s are both referred to as %s", existingImportPath, importPath, packageName)
+	}
+
+	extractor.imports[packageName] = importPath
+	return nil
+}
+
+// loadImportedPackage loads the package with the import path, returning the name it is referred to by from the interface's package.
+// The qualifier is empty if the import path is of the interface's package itself.
+func (extractor *interfaceExtractor) loadImportedPackage(importPath string) (*goPackage, string, error) {
+	dirPath, err := extractor.resolver.DirForImportPath(importPath)
+	if err != nil {
+		return nil, "", err
+	}
+	dirPath, err = filepath.Abs(dirPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if dirPath == extractor.targetPackage.dirPath {
+		return extractor.targetPackage, "", nil
+	}
+
+	pkg, err := loadGoPackage(dirPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = extractor.addImport(pkg.name, importPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return pkg, pkg.name, nil
+}
+
+// filterMethods leaves out the methods not in options.Methods, or in options.ExcludeMethods.
+// Embedded interfaces are left out with options.Methods, and kept with options.ExcludeMethods.
+func (extractor *interfaceExtractor) filterMethods(options ExtractOptions) error {
+	methodNames := make(map[string]struct{})
+	for _, element := range extractor.elements {
+		methodNames[element.Name] = struct{}{}
+		for _, method := range element.Methods {
+			methodNames[method.Name] = struct{}{}
+		}
+	}
+	for _, methodName := range append(options.Methods, options.ExcludeMethods...) {
+		if _, ok := methodNames[methodName]; !ok || methodName == "" {
+			return fmt.Errorf("method %s not found in the method set of the type", methodName)
+		}
+	}
+
+	isIncluded := func(methodName string) bool {
+		if len(options.Methods) != 0 {
+			return containsString(options.Methods, methodName)
+		}
+		return !containsString(options.ExcludeMethods, methodName)
+	}
+
+	var elements []interfaceElement
+	for _, element := range extractor.elements {
+		if element.Name != "" {
+			if isIncluded(element.Name) {
+				elements = append(elements, element)
+			}
+			continue
+		}
+
+		// embedded interfaces are kept if all their methods are included, and replaced by the included methods otherwise
+		var includedMethods []interfaceElement
+		for _, method := range element.Methods {
+			if _, ok := extractor.methodNames[method.Name]; ok {
+				// shadowed by a method of the type
+				continue
+			}
+			if isIncluded(method.Name) {
+				includedMethods = append(includedMethods, method)
+			}
+		}
+		if len(element.Methods) == 0 {
+			// the methods aren't known
+			if len(options.Methods) == 0 {
+				elements = append(elements, element)
+			}
+			continue
+		}
+		if len(includedMethods) == len(element.Methods) {
+			elements = append(elements, element)
+			continue
+		}
+		for packageName, importPath := range element.Imports {
+			err := extractor.addImport(packageName, importPath)
+			if err != nil {
+				return err
+			}
+		}
+		elements = append(elements, includedMethods...)
+	}
+	extractor.elements = elements
+
+	return nil
+}
+
+// render writes the file declaring the interface, formatted with gofmt
+func (extractor *interfaceExtractor) render(interfaceName, doc, header string) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	if header := commentText(header); header != "" {
+		fmt.Fprintf(buf, "%s\n\n", header)
+	}
+	fmt.Fprintf(buf, "// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", extractor.targetPackage.name)
+
+	var usedPackageNames []string
+	for _, element := range extractor.elements {
+		usedPackageNames = append(usedPackageNames, packagesInTypeExpr("interface{ "+element.Text+" }")...)
+	}
+	var imports []string
+	for packageName, importPath := range extractor.imports {
+		if !containsString(usedPackageNames, packageName) {
+			continue
+		}
+		importText := strconv.Quote(importPath)
+		if path.Base(importPath) != packageName {
+			importText = packageName + " " + importText
+		}
+		imports = append(imports, importText)
+	}
+	if len(imports) != 0 {
+		sort.Strings(imports)
+		fmt.Fprintf(buf, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+	}
+
+	fmt.Fprintf(buf, "%s\n", comment(doc))
+	fmt.Fprintf(buf, "type %s interface {\n", interfaceName)
+	for _, element := range extractor.elements {
+		if element.Doc != "" {
+			fmt.Fprintf(buf, "%s\n", comment(element.Doc))
+		}
+		fmt.Fprintf(buf, "\t%s\n", element.Text)
+	}
+	fmt.Fprintf(buf, "}\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("generated interface is not valid Go (%s). Generated code:\n%s", err, buf.String())
+	}
+
+	return string(formatted), nil
+}
+
+// receiverType returns the name of the type of a method's receiver, and whether it is a pointer receiver
+func receiverType(funcDecl *ast.FuncDecl) (string, bool) {
+	if len(funcDecl.Recv.List) == 0 {
+		return "", false
+	}
+
+	receiverTypeExpr := funcDecl.Recv.List[0].Type
+	starExpr, pointer := receiverTypeExpr.(*ast.StarExpr)
+	if pointer {
+		receiverTypeExpr = starExpr.X
+	}
+
+	ident, ok := receiverTypeExpr.(*ast.Ident)
+	if !ok {
+		// generic receiver types, e.g. `(s *Set[T])`
+		return "", pointer
+	}
+
+	return ident.Name, pointer
+}
+
+// goPackage is the parsed non-test Go files of a package
+type goPackage struct {
+	dirPath, name string
+	fileSet       *token.FileSet
+	files         []*goFile
+}
+
+type goFile struct {
+	path, source string
+	file         *ast.File
+	// base is the position of the start of the file in the package's file set
+	base int
+}
+
+// nodeSource returns the source code of a node of the file
+func (file *goFile) nodeSource(node ast.Node) string {
+	return file.source[int(node.Pos())-file.base : int(node.End())-file.base]
+}
+
+// loadGoPackage parses the Go files in the directory, leaving out tests and the files excluded by build constraints
+func loadGoPackage(dirPath string) (*goPackage, error) {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %q", err)
+	}
+
+	pkg := &goPackage{dirPath: dirPath, fileSet: token.NewFileSet()}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), ".go") || strings.HasSuffix(fileInfo.Name(), "_test.go") {
+			continue
+		}
+		match, err := build.Default.MatchFile(dirPath, fileInfo.Name())
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, fileInfo.Name())
+		source, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read %q. Error: %q", filePath, err)
+		}
+
+		file, err := parser.ParseFile(pkg.fileSet, filePath, source, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		}
+		pkg.files = append(pkg.files, &goFile{path: filePath, source: string(source), file: file, base: pkg.fileSet.File(file.Pos()).Base()})
+	}
+
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go files found in %s", dirPath)
+	}
+
+	return pkg, nil
+}
+
+// findTypeSpec finds the declaration of the named type in the package, and the file it is in
+func (pkg *goPackage) findTypeSpec(typeName string) (*ast.TypeSpec, *goFile) {
+	for _, file := range pkg.files {
+		for _, decl := range file.file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Name.Name == typeName {
+					return typeSpec, file
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// importPathForName returns the import path of the package imported with the name in any of the package's files, or an empty string
+func (pkg *goPackage) importPathForName(packageName string) string {
+	for _, file := range pkg.files {
+		importPath := file.importPathForName(packageName)
+		if importPath != "" {
+			return importPath
+		}
+	}
+
+	return ""
+}
+
+// importPathForName returns the import path of the package imported with the name in the file, or an empty string
+func (file *goFile) importPathForName(packageName string) string {
+	importPath, err := importPathForPackageName(file.file.Imports, packageName)
+	if err != nil {
+		return ""
+	}
+
+	return importPath
+}
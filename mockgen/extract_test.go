@@ -0,0 +1,124 @@
+package mockgen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractInterface(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-extract-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	writeTestFiles(t, dirPath, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"pgstore/store.go": `package pgstore
+
+import (
+	"context"
+	"io"
+)
+
+type Item struct{}
+
+type conn struct{}
+
+// Ping checks the connection
+func (c *conn) Ping(ctx context.Context) error { return nil }
+
+type Store struct {
+	*conn
+	io.Closer
+}
+
+// Get gets an item
+func (s *Store) Get(ctx context.Context, key string) (Item, error) { return Item{}, nil }
+
+func (s Store) Name() string { return "" }
+
+func (s *Store) reset() {}
+`,
+		"pgstore/store_custom.go": `//go:build pgstore_custom
+
+package pgstore
+
+// Socket returns the path of the server's socket
+func (s *Store) Socket() (string, error) { return "", nil }
+`,
+		"pgstore/store_default.go": `//go:build !pgstore_custom
+
+package pgstore
+
+// Socket returns the path of the server's socket
+func (s *Store) Socket() string { return "/run/postgresql" }
+`,
+		"app/app.go": `package app
+
+import "example.com/shop/pgstore"
+
+type Service struct {
+	store *pgstore.Store
+}
+`,
+	})
+
+	appDirPath := filepath.Join(dirPath, "app")
+	resolver, err := NewResolver(appDirPath)
+	require.NoError(t, err)
+
+	extractedInterface, err := ExtractInterface(appDirPath, "*pgstore.Store", ExtractOptions{}, resolver)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Store", extractedInterface.Name)
+	assert.Equal(t, `// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+	"example.com/shop/pgstore"
+	"io"
+)
+
+// Store is the interface of the exported methods of *pgstore.Store.
+type Store interface {
+	// Get gets an item
+	Get(ctx context.Context, key string) (pgstore.Item, error)
+	Name() string
+	// Socket returns the path of the server's socket
+	Socket() string
+	// Ping checks the connection
+	Ping(ctx context.Context) error
+	io.Closer
+}
+`, extractedInterface.Text)
+
+	typeData, err := GetMethodsForType(extractedInterface.Text, extractedInterface.Name)
+	require.NoError(t, err)
+	_, err = WriteMockType(extractedInterface.Name, typeData, Options{})
+	require.NoError(t, err)
+
+	t.Run("value type", func(t *testing.T) {
+		extractedInterface, err := ExtractInterface(appDirPath, "example.com/shop/pgstore.Store", ExtractOptions{InterfaceName: "Namer", Methods: []string{"Name"}}, resolver)
+		require.NoError(t, err)
+
+		assert.Contains(t, extractedInterface.Text, `type Namer interface {
+	Name() string
+}`)
+	})
+
+	t.Run("pointer receiver not in the method set", func(t *testing.T) {
+		_, err := ExtractInterface(appDirPath, "pgstore.Store", ExtractOptions{Methods: []string{"Get"}}, resolver)
+		assert.EqualError(t, err, "method Get not found in the method set of the type")
+	})
+
+	t.Run("type in the same package", func(t *testing.T) {
+		_, err := ExtractInterface(filepath.Join(dirPath, "pgstore"), "*Store", ExtractOptions{}, resolver)
+		assert.EqualError(t, err, "a name is needed for the interface, as Store is declared in the same package")
+	})
+}
@@ -0,0 +1,168 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strconv"
+)
+
+// errCantFlatten is returned for embedded interfaces that can't be flattened, and are kept embedded in the mock
+var errCantFlatten = errors.New("embedded interface can't be flattened")
+
+// FlattenEmbeddedInterfaces adds the methods of the embedded interfaces to the interface's methods, so that the mock gets fields for them,
+// rather than embedding the interfaces. The declarations of the embedded interfaces are loaded with the resolver,
+// dirPath being the directory of the interface's package.
+//
+// Embedded interfaces that can't be flattened, e.g. instantiated generic interfaces or type aliases, are kept as embedded interfaces.
+func FlattenEmbeddedInterfaces(typeData *TypeData, dirPath string, resolver *Resolver) error {
+	methodsByName := make(map[string]Method)
+	for _, method := range typeData.Methods {
+		methodsByName[method.Name] = method
+	}
+
+	var embeddedInterfaces []string
+	for _, embeddedInterface := range typeData.EmbeddedInterfaces {
+		embeddedTypeData, err := loadEmbeddedInterface(typeData, embeddedInterface, dirPath, resolver)
+		if err != nil {
+			if err == errCantFlatten {
+				embeddedInterfaces = appendIfMissing(embeddedInterfaces, embeddedInterface)
+				continue
+			}
+			return fmt.Errorf("couldn't flatten embedded interface %s: %s", embeddedInterface, err)
+		}
+
+		typeData.FlattenedInterfaces = appendIfMissing(typeData.FlattenedInterfaces, embeddedInterface)
+		for _, flattenedInterface := range embeddedTypeData.FlattenedInterfaces {
+			typeData.FlattenedInterfaces = appendIfMissing(typeData.FlattenedInterfaces, flattenedInterface)
+		}
+		for _, nestedInterface := range embeddedTypeData.EmbeddedInterfaces {
+			embeddedInterfaces = appendIfMissing(embeddedInterfaces, nestedInterface)
+		}
+
+		for _, method := range embeddedTypeData.Methods {
+			if method.Interface == "" {
+				method.Interface = embeddedInterface
+			}
+			if existingMethod, ok := methodsByName[method.Name]; ok {
+				if existingMethod.signature() != method.signature() {
+					return fmt.Errorf("method %s%s of %s conflicts with method %s%s of %s",
+						method.Name, method.signature(), method.Interface, existingMethod.Name, existingMethod.signature(), methodInterfaceName(existingMethod))
+				}
+				// the same method through several embedded interfaces
+				continue
+			}
+			methodsByName[method.Name] = method
+			typeData.Methods = append(typeData.Methods, method)
+		}
+
+		for _, im := range embeddedTypeData.Imports {
+			if !hasImportSpec(typeData.Imports, im) {
+				typeData.Imports = append(typeData.Imports, im)
+			}
+		}
+	}
+	typeData.EmbeddedInterfaces = embeddedInterfaces
+
+	return nil
+}
+
+// loadEmbeddedInterface loads the declaration of an interface embedded in the interface, and flattens it.
+// The types in it are qualified as they are referred to from the interface's package.
+func loadEmbeddedInterface(typeData *TypeData, embeddedInterface, dirPath string, resolver *Resolver) (*TypeData, error) {
+	expr, err := parser.ParseExpr(embeddedInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	var packageName, interfaceName string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		// in the same package
+		interfaceName = e.Name
+	case *ast.SelectorExpr:
+		packageIdent, ok := e.X.(*ast.Ident)
+		if !ok {
+			return nil, errCantFlatten
+		}
+		packageName, interfaceName = packageIdent.Name, e.Sel.Name
+	default:
+		return nil, errCantFlatten
+	}
+
+	embeddedDirPath := dirPath
+	if packageName != "" {
+		importPath, err := importPathForPackageName(typeData.Imports, packageName)
+		if err != nil {
+			return nil, err
+		}
+		embeddedDirPath, err = resolver.DirForImportPath(importPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	embeddedTypeData, err := GetMethodsForTypeInDir(embeddedDirPath, interfaceName)
+	if err != nil {
+		if err == ErrInterfaceTypeNotFound {
+			// e.g. a type alias of an interface, such as `type FileInfo = fs.FileInfo`
+			return nil, errCantFlatten
+		}
+		return nil, err
+	}
+
+	if embeddedTypeData.Func {
+		return nil, fmt.Errorf("%s is a function type, not an interface", embeddedInterface)
+	}
+
+	err = FlattenEmbeddedInterfaces(embeddedTypeData, embeddedDirPath, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	if packageName == "" {
+		return embeddedTypeData, nil
+	}
+
+	return qualifyTypeDataWith(embeddedTypeData, packageName)
+}
+
+// methodInterfaceName returns the name of the interface declaring the method, for error messages
+func methodInterfaceName(method Method) string {
+	if method.Interface == "" {
+		return "the interface"
+	}
+	return method.Interface
+}
+
+// importPathForPackageName finds the import path of the package imported with the name
+func importPathForPackageName(imports []*ast.ImportSpec, packageName string) (string, error) {
+	for _, im := range imports {
+		if importShortName(im) == packageName {
+			return strconv.Unquote(im.Path.Value)
+		}
+	}
+
+	return "", fmt.Errorf("no import found for package %s", packageName)
+}
+
+func hasImportSpec(imports []*ast.ImportSpec, im *ast.ImportSpec) bool {
+	for _, existingImport := range imports {
+		if existingImport.Path.Value == im.Path.Value && existingImport.Name.String() == im.Name.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, existingValue := range values {
+		if existingValue == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
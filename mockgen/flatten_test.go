@@ -0,0 +1,116 @@
+package mockgen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenEmbeddedInterfaces(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-flatten-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	writeTestFiles(t, dirPath, map[string]string{
+		"go.work":  "go 1.21\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.21\n\nrequire example.com/b v0.0.0\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.21\n",
+		"a/store/store.go": `package store
+
+import (
+	"example.com/b/health"
+)
+
+type Item struct{}
+
+type Store interface {
+	Get(key string) (Item, error)
+	health.Checker
+	Lister
+}
+
+type Lister interface {
+	List() []Item
+}
+`,
+		"b/health/health.go": `package health
+
+import "io"
+
+type Status int
+
+type Checker interface {
+	// Check checks the health
+	Check() Status
+	Pinger
+	io.Closer
+}
+
+type Pinger interface {
+	Ping(timeout int) error
+}
+`,
+	})
+
+	storeDirPath := filepath.Join(dirPath, "a", "store")
+	typeData, err := GetMethodsForTypeInDir(storeDirPath, "Store")
+	require.NoError(t, err)
+
+	resolver, err := NewResolver(storeDirPath)
+	require.NoError(t, err)
+
+	err = FlattenEmbeddedInterfaces(typeData, storeDirPath, resolver)
+	require.NoError(t, err)
+
+	assert.Empty(t, typeData.EmbeddedInterfaces)
+	assert.Equal(t, []string{"health.Checker", "health.Pinger", "io.Closer", "Lister"}, typeData.FlattenedInterfaces)
+
+	mockText, err := WriteMockType("Store", typeData, Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `import (
+	"example.com/b/health"
+	"io"
+	"sync"
+)
+
+// MockStore is a mock implementation of [Store].
+type MockStore struct {
+	// GetFunc is called by [MockStore.Get].
+	GetFunc func(key string) (Item, error)
+	// CheckFunc is called by [MockStore.Check].
+	//
+	// Check checks the health
+	CheckFunc func() health.Status
+	// PingFunc is called by [MockStore.Ping].
+	PingFunc func(timeout int) error
+	// CloseFunc is called by [MockStore.Close].
+	CloseFunc func() error
+	// ListFunc is called by [MockStore.List].
+	ListFunc func() []Item
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Get   []MockStoreGetCall
+		Check []MockStoreCheckCall
+		Ping  []MockStorePingCall
+		Close []MockStoreCloseCall
+		List  []MockStoreListCall
+	}
+}
+
+var (
+	// compile time checks that *MockStore implements the interface and the interfaces it embeds
+	_ Store          = &MockStore{}
+	_ health.Checker = &MockStore{}
+	_ health.Pinger  = &MockStore{}
+	_ io.Closer      = &MockStore{}
+	_ Lister         = &MockStore{}
+)
+`)
+}
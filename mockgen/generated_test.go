@@ -0,0 +1,779 @@
+package mockgen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedMocksTest is a package of mocks, along with a test using them
+type generatedMocksTest struct {
+	name string
+	// source declares the interfaces, in package example
+	source string
+	mocks  []generatedMocksTestMock
+	// test is the test file using the mocks. The tests named TestFails... are expected to fail, the others to pass.
+	test string
+	// failures are the failures expected from the failing tests
+	failures []generatedMocksTestFailure
+}
+
+type generatedMocksTestMock struct {
+	typeName string
+	options  Options
+}
+
+type generatedMocksTestFailure struct {
+	message string
+	// marker is a comment on the line of the test the failure must be reported at, e.g. `// reported here`.
+	// If empty, the failure can be reported anywhere.
+	marker string
+}
+
+var generatedMocksTests = []generatedMocksTest{
+	{
+		name: "receiver",
+		source: `package example
+
+type Writer interface {
+	Write(o []byte) (int, error)
+}
+`,
+		mocks: []generatedMocksTestMock{{typeName: "Writer", options: Options{
+			Testing:      true,
+			Expectations: true,
+			Returns:      true,
+			Synchronized: true,
+			Order:        true,
+		}}},
+		test: `package example
+
+import (
+	"testing"
+
+	"github.com/jamesrr39/go-mockgen-tool/match"
+)
+
+func TestReceiver(t *testing.T) {
+	writer := NewMockWriter(t)
+	writer.ExpectWrite(match.Any[[]byte]()).Return(1, nil)
+	if n, _ := writer.Write([]byte("a")); n != 1 {
+		t.Errorf("unexpected result of the expected call: %d", n)
+	}
+
+	writer.WriteReturns(2, nil)
+	if n, _ := writer.Write([]byte("a")); n != 2 {
+		t.Errorf("unexpected result set with WriteReturns: %d", n)
+	}
+
+	writer.SetWriteFunc(func(o []byte) (int, error) {
+		return len(o), nil
+	})
+	if n, _ := writer.Write([]byte("abc")); n != 3 {
+		t.Errorf("unexpected result of WriteFunc: %d", n)
+	}
+}
+`,
+	},
+	{
+		name: "calls",
+		source: `package example
+
+type Store interface {
+	Put(key string, tags ...string) error
+	tag(string, int)
+	//mockgen:skip
+	Reset()
+}
+`,
+		mocks: []generatedMocksTestMock{{typeName: "Store"}},
+		test: `package example
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCalls(t *testing.T) {
+	store := &MockStore{PutFunc: func(key string, tags ...string) error {
+		return nil
+	}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Put("key", "a", "b")
+		}()
+	}
+	wg.Wait()
+
+	calls := store.PutCalls()
+	if store.PutCallCount() != 20 || len(calls) != 20 || calls[0].Key != "key" || len(calls[0].Tags) != 2 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+}
+
+func TestCallsUnnamedParameters(t *testing.T) {
+	store := &MockStore{tagFunc: func(string, int) {}}
+	store.tag("a", 1)
+
+	// unexported methods get unexported accessors, and unnamed parameters get fields named after their position
+	if calls := store.tagCalls(); len(calls) != 1 || calls[0].Param0 != "a" || calls[0].Param1 != 1 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+}
+`,
+	},
+	{
+		name: "unset",
+		source: `package example
+
+import "errors"
+
+var ErrReadOnly = errors.New("read only")
+
+type Store interface {
+	Get(key string) (string, error)
+	Count() int
+	//mockgen:default-return ErrReadOnly
+	Put(key string, value string) error
+}
+
+type Cache interface {
+	Get(key string) (*string, error)
+	//mockgen:default-return ErrReadOnly
+	Put(key string, value string) error
+}
+
+type Queue interface {
+	Pop() (string, error)
+}
+`,
+		mocks: []generatedMocksTestMock{
+			{typeName: "Store", options: Options{OnUnset: OnUnsetError}},
+			{typeName: "Cache", options: Options{OnUnset: OnUnsetZero}},
+			{typeName: "Queue", options: Options{OnUnset: OnUnsetPanic}},
+		},
+		test: `package example
+
+import "testing"
+
+func TestUnset(t *testing.T) {
+	store := &MockStore{}
+
+	if _, err := store.Get("key"); err == nil || err.Error() != "MockStore.Get: GetFunc not defined" {
+		t.Errorf("unexpected error from Get: %v", err)
+	}
+	if count := store.Count(); count != 0 {
+		t.Errorf("unexpected count: %d", count)
+	}
+	if err := store.Put("key", "value"); err != ErrReadOnly {
+		t.Errorf("unexpected error from Put: %v", err)
+	}
+}
+
+func TestUnsetZero(t *testing.T) {
+	cache := &MockCache{}
+
+	if value, err := cache.Get("key"); value != nil || err != nil {
+		t.Errorf("unexpected results from Get: %v, %v", value, err)
+	}
+	if err := cache.Put("key", "value"); err != ErrReadOnly {
+		t.Errorf("unexpected error from Put: %v", err)
+	}
+}
+
+func TestUnsetPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "PopFunc not defined" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+
+	queue := &MockQueue{}
+	queue.Pop()
+}
+`,
+	},
+	{
+		name: "testing",
+		source: `package example
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key string, value string) error
+}
+
+type Cache interface {
+	Get(key string) (string, error)
+	Put(key string, value string) error
+}
+
+type Queue interface {
+	Pop() (string, error)
+}
+
+type MapCache map[string]string
+
+func (c MapCache) Get(key string) (string, error) {
+	return c[key], nil
+}
+
+func (c MapCache) Put(key string, value string) error {
+	c[key] = value
+	return nil
+}
+`,
+		mocks: []generatedMocksTestMock{
+			{typeName: "Store", options: Options{Testing: true}},
+			{typeName: "Cache", options: Options{Testing: true, Methods: []string{"Put"}}},
+			{typeName: "Queue", options: Options{Testing: true, OnUnset: OnUnsetZero}},
+		},
+		test: `package example
+
+import "testing"
+
+func TestTesting(t *testing.T) {
+	store := NewMockStore(t)
+	store.GetFunc = func(key string) (string, error) {
+		return "value", nil
+	}
+
+	if value, err := store.Get("key"); err != nil || value != "value" {
+		t.Errorf("unexpected result from Get: %q, %v", value, err)
+	}
+}
+
+func TestTestingDelegate(t *testing.T) {
+	cache := NewMockCache(t, MapCache{"key": "value"})
+	cache.PutFunc = func(key string, value string) error {
+		return nil
+	}
+
+	cache.Put("key", "other value")
+	if value, _ := cache.Get("key"); value != "value" {
+		t.Errorf("unexpected value from the delegate: %q", value)
+	}
+}
+
+func TestTestingZero(t *testing.T) {
+	// unset fields return the zero values rather than failing the test
+	queue := NewMockQueue(t)
+	if value, err := queue.Pop(); value != "" || err != nil {
+		t.Errorf("unexpected results: %q, %v", value, err)
+	}
+}
+
+func TestFailsUnsetField(t *testing.T) {
+	store := NewMockStore(t)
+	store.Put("key", "value") // reported here
+	t.Error("not reached")
+}
+
+func TestFailsNotCalled(t *testing.T) {
+	store := NewMockStore(t)
+	store.PutFunc = func(key string, value string) error {
+		return nil
+	}
+}
+`,
+		failures: []generatedMocksTestFailure{
+			{message: "MockStore.Put: PutFunc not defined", marker: "// reported here"},
+			{message: "MockStore.PutFunc is set, but MockStore.Put was never called"},
+		},
+	},
+	{
+		name: "expect",
+		source: `package example
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key string, value int) error
+	Reset()
+}
+`,
+		mocks: []generatedMocksTestMock{{typeName: "Store", options: Options{Expectations: true}}},
+		test: `package example
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/jamesrr39/go-mockgen-tool/match"
+)
+
+func TestExpect(t *testing.T) {
+	store := NewMockStore(t)
+	store.ExpectGet(match.Eq("a")).Times(10).Return("value a", nil)
+	store.ExpectGet(match.Any[string]()).AnyTimes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if value, _ := store.Get("a"); value != "value a" {
+				t.Errorf("unexpected value: %q", value)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			store.Get("b")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestExpectParametersAndResults(t *testing.T) {
+	store := NewMockStore(t)
+	store.ExpectPut(match.Eq("a"), match.Func("a positive number", func(n int) bool { return n > 0 })).Return(nil)
+	store.ExpectReset()
+
+	if err := store.Put("a", 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	store.Reset()
+}
+
+func TestFailsMissingCall(t *testing.T) {
+	store := NewMockStore(t)
+	store.ExpectGet(match.Eq("a"))
+}
+
+func TestFailsUnexpectedCall(t *testing.T) {
+	store := NewMockStore(t)
+	store.ExpectGet(match.Eq("a")).AnyTimes()
+	store.Get("b") // reported here
+}
+`,
+		failures: []generatedMocksTestFailure{
+			{message: `missing call to MockStore.Get("a"): called 0 of 1 times`},
+			{message: `unexpected call to MockStore.Get("b")`, marker: "// reported here"},
+		},
+	},
+	{
+		name: "returns",
+		source: `package example
+
+type Store interface {
+	Get(key string) (string, error)
+	// Reset has no results to set
+	Reset()
+}
+`,
+		mocks: []generatedMocksTestMock{{typeName: "Store", options: Options{Returns: true, Testing: true}}},
+		test: `package example
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestReturns(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	store := NewMockStore(t)
+	store.GetReturns("value", nil)
+	store.GetReturnsOnCall(1, "", errTimeout)
+
+	if value, err := store.Get("key"); value != "value" || err != nil {
+		t.Errorf("unexpected results of the first call: %q, %v", value, err)
+	}
+	if _, err := store.Get("key"); err != errTimeout {
+		t.Errorf("unexpected error from the second call: %v", err)
+	}
+}
+
+func TestReturnsSequence(t *testing.T) {
+	store := NewMockStore(t)
+	var sequence []MockStoreGetResults
+	for i := 0; i < 20; i++ {
+		sequence = append(sequence, MockStoreGetResults{Result0: fmt.Sprint(i)})
+	}
+	store.GetReturnsSequence(sequence...)
+
+	var wg sync.WaitGroup
+	values := make(chan string, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _ := store.Get("key")
+			values <- value
+		}()
+	}
+	wg.Wait()
+	close(values)
+
+	// each call gets its own results
+	seen := make(map[string]bool)
+	for value := range values {
+		seen[value] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("unexpected values: %v", seen)
+	}
+}
+
+func TestFailsSequenceExhausted(t *testing.T) {
+	store := NewMockStore(t)
+	store.GetReturnsSequence(MockStoreGetResults{Result0: "first"})
+	store.Get("key")
+	store.Get("key") // reported here
+	t.Error("not reached")
+}
+
+func TestFailsResultsNotUsed(t *testing.T) {
+	store := NewMockStore(t)
+	store.GetReturns("value", nil)
+}
+`,
+		failures: []generatedMocksTestFailure{
+			{message: "MockStore.Get called 2 times, but GetReturnsSequence only has 1 results", marker: "// reported here"},
+			{message: "results are set for MockStore.Get, but MockStore.Get was never called"},
+		},
+	},
+	{
+		name: "order",
+		source: `package example
+
+type Tx interface {
+	Begin() error
+	Commit() error
+}
+
+type Store interface {
+	Put(key string) error
+	//mockgen:skip
+	//mockgen:default-return nil
+	Close() error
+}
+`,
+		mocks: []generatedMocksTestMock{
+			{typeName: "Tx", options: Options{Order: true, OnUnset: OnUnsetZero}},
+			{typeName: "Store", options: Options{Order: true, OnUnset: OnUnsetZero}},
+		},
+		test: `package example
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/jamesrr39/go-mockgen-tool/callorder"
+)
+
+func newMocks() (*callorder.Recorder, *MockTx, *MockStore) {
+	calls := &callorder.Recorder{}
+	tx := &MockTx{}
+	tx.RecordOrder(calls)
+	store := &MockStore{}
+	store.RecordOrder(calls)
+	return calls, tx, store
+}
+
+func TestOrder(t *testing.T) {
+	calls, tx, store := newMocks()
+
+	tx.Begin()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Put("a")
+		}()
+	}
+	wg.Wait()
+	tx.Commit()
+	// skipped methods aren't recorded
+	store.Close()
+
+	calls.InOrder(t, tx.BeginCalled(), store.PutCalled(), tx.CommitCalled())
+	if len(calls.Calls()) != 12 {
+		t.Errorf("unexpected calls: %v", calls.Calls())
+	}
+}
+
+func TestFailsOrder(t *testing.T) {
+	calls, tx, store := newMocks()
+
+	tx.Begin()
+	tx.Commit()
+	store.Put("a")
+
+	calls.InOrder(t, tx.BeginCalled(), store.PutCalled(), tx.CommitCalled()) // reported here
+}
+`,
+		failures: []generatedMocksTestFailure{
+			{
+				message: "calls not in the expected order: MockTx.Commit was not called after MockStore.Put",
+				marker:  "// reported here",
+			},
+		},
+	},
+	{
+		name: "sync",
+		source: `package example
+
+type Counter interface {
+	Add(n int) int
+	Next(mockFunc int) int
+}
+`,
+		mocks: []generatedMocksTestMock{{typeName: "Counter", options: Options{Synchronized: true}}},
+		test: `package example
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	counter := &MockCounter{AddFunc: func(n int) int {
+		return n
+	}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			counter.Add(1)
+		}()
+		go func(i int) {
+			defer wg.Done()
+			counter.SetAddFunc(func(n int) int {
+				return n + i
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	counter.SetAddFunc(func(n int) int {
+		return -n
+	})
+	if result := counter.Add(1); result != -1 {
+		t.Errorf("unexpected result: %d", result)
+	}
+
+	counter.SetNextFunc(func(mockFunc int) int {
+		return mockFunc + 1
+	})
+	if result := counter.Next(1); result != 2 {
+		t.Errorf("unexpected result of Next: %d", result)
+	}
+}
+`,
+	},
+	{
+		name: "spy",
+		source: `package example
+
+import "errors"
+
+type Store interface {
+	Get(key string) (string, error)
+	Reset()
+}
+
+type MapStore map[string]string
+
+func (s MapStore) Get(key string) (string, error) {
+	value, ok := s[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func (s MapStore) Reset() {}
+
+type Namer interface {
+	Name(result0, mockCallIndex string) string
+}
+
+type JoinNamer struct{}
+
+func (JoinNamer) Name(result0, mockCallIndex string) string {
+	return result0 + mockCallIndex
+}
+`,
+		mocks: []generatedMocksTestMock{
+			{typeName: "Store", options: Options{Spy: true}},
+			{typeName: "Namer", options: Options{Spy: true, Testing: true, Naming: Naming{MockSuffix: "Spy"}}},
+		},
+		test: `package example
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSpy(t *testing.T) {
+	store := NewSpyStore(MapStore{"a": "value a"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if value, err := store.Get("a"); value != "value a" || err != nil {
+				t.Errorf("unexpected results from the real implementation: %q, %v", value, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			store.Get("b")
+		}()
+	}
+	wg.Wait()
+	store.Reset()
+
+	calls := store.GetCalls()
+	if len(calls) != 20 || store.ResetCallCount() != 1 {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	for _, call := range calls {
+		switch {
+		case call.Key == "a" && call.Results.Result0 == "value a" && call.Results.Result1 == nil:
+		case call.Key == "b" && call.Results.Result0 == "" && call.Results.Result1 != nil:
+		default:
+			t.Errorf("unexpected call: %+v", call)
+		}
+	}
+}
+
+func TestSpyField(t *testing.T) {
+	store := NewSpyStore(MapStore{})
+	store.GetFunc = func(key string) (string, error) {
+		return "mocked", nil
+	}
+
+	if value, _ := store.Get("a"); value != "mocked" {
+		t.Errorf("unexpected value: %q", value)
+	}
+	if results := store.GetCalls()[0].Results; results.Result0 != "mocked" {
+		t.Errorf("unexpected results recorded: %+v", results)
+	}
+}
+
+func TestSpyTestingWithParametersNamedLikeTheResults(t *testing.T) {
+	namer := NewNamerSpy(t, JoinNamer{})
+
+	if name := namer.Name("a", "b"); name != "ab" {
+		t.Errorf("unexpected name: %q", name)
+	}
+	if calls := namer.NameCalls(); len(calls) != 1 || calls[0].Result0 != "a" || calls[0].Results.Result0 != "ab" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+}
+`,
+	},
+}
+
+// TestGeneratedMocks generates mocks into a temporary module, and runs tests using them with the race detector
+func TestGeneratedMocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test on the generated mocks")
+	}
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is needed to run the generated mocks")
+	}
+
+	dirPath, err := ioutil.TempDir("", "go-mockgen-tool-generated-mocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	moduleDirPath, err := filepath.Abs("..")
+	require.NoError(t, err)
+	goMod := fmt.Sprintf("module example.com/mocks\n\ngo 1.18\n\nrequire github.com/jamesrr39/go-mockgen-tool v0.0.0\n\nreplace github.com/jamesrr39/go-mockgen-tool => %s\n", filepath.ToSlash(moduleDirPath))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "go.mod"), []byte(goMod), 0644))
+	goSum, err := ioutil.ReadFile(filepath.Join(moduleDirPath, "go.sum"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "go.sum"), goSum, 0644))
+
+	for _, test := range generatedMocksTests {
+		packageDirPath := filepath.Join(dirPath, test.name)
+		require.NoError(t, os.Mkdir(packageDirPath, 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(packageDirPath, "example.go"), []byte(test.source), 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(packageDirPath, "example_test.go"), []byte(test.test), 0644))
+
+		for _, mock := range test.mocks {
+			typeData, err := GetMethodsForType(test.source, mock.typeName)
+			require.NoError(t, err, test.name)
+			options := mock.options
+			if options.GoVersion == "" {
+				options.GoVersion = "1.18"
+			}
+			mockText, err := WriteMockType(mock.typeName, typeData, options)
+			require.NoError(t, err, test.name)
+			mockFileName := strings.ToLower(mock.typeName) + "_mock_test.go"
+			require.NoError(t, ioutil.WriteFile(filepath.Join(packageDirPath, mockFileName), []byte(mockText), 0644))
+		}
+	}
+
+	cmd := exec.Command(goBinary, "test", "-race", "-v", "-count=1", "./...")
+	cmd.Dir = dirPath
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	outputBytes, _ := cmd.CombinedOutput()
+	output := string(outputBytes)
+
+	for _, test := range generatedMocksTests {
+		t.Run(test.name, func(t *testing.T) {
+			packageOutput := packageTestOutput(output, test.name)
+			require.NotEmpty(t, packageOutput, "no output for package %s:\n%s", test.name, output)
+			require.Regexp(t, `--- (PASS|FAIL)`, packageOutput, "no tests run")
+
+			for _, match := range regexp.MustCompile(`--- (PASS|FAIL): (\w+)`).FindAllStringSubmatch(packageOutput, -1) {
+				expectFailure := strings.HasPrefix(match[2], "TestFails")
+				assert.Equal(t, expectFailure, match[1] == "FAIL", "%s: %s\n%s", match[1], match[2], packageOutput)
+			}
+
+			testLines := strings.Split(test.test, "\n")
+			for _, failure := range test.failures {
+				match := regexp.MustCompile(`(\w+\.go):(\d+): ` + regexp.QuoteMeta(failure.message)).FindStringSubmatch(packageOutput)
+				if !assert.NotNil(t, match, "failure %q not found in:\n%s", failure.message, packageOutput) || failure.marker == "" {
+					continue
+				}
+				line, err := strconv.Atoi(match[2])
+				require.NoError(t, err)
+				assert.Equal(t, "example_test.go", match[1], failure.message)
+				if assert.True(t, line <= len(testLines), failure.message) {
+					assert.Contains(t, testLines[line-1], failure.marker, failure.message)
+				}
+			}
+		})
+	}
+}
+
+// packageTestOutput returns the output of the tests of the package in the module's directory, from the output of `go test -v ./...`
+func packageTestOutput(output, packageName string) string {
+	// the output of each package ends with its `ok`/`FAIL` line
+	var packageOutput []string
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		lines = append(lines, line)
+		if strings.HasPrefix(line, "ok  \t") || strings.HasPrefix(line, "FAIL\t") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && fields[1] == "example.com/mocks/"+packageName {
+				packageOutput = append(packageOutput, lines...)
+			}
+			lines = nil
+		}
+	}
+
+	return strings.Join(packageOutput, "\n")
+}
@@ -0,0 +1,115 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// minor versions of Go that the generated code depends on
+const (
+	// goVersionGoBuildLines is the first version that doesn't need `// +build` lines alongside `//go:build` lines
+	goVersionGoBuildLines = 17
+	// goVersionGenerics is the first version with generics and the predeclared `any` type
+	goVersionGenerics = 18
+)
+
+var goVersionRegex = regexp.MustCompile(`^(?:go)?1\.(\d+)(?:\.\d+|(?:rc|beta)\d+)?$`)
+
+// goVersion is the minor version of Go the mock is generated for, e.g. 18 for Go 1.18. It is 0 if the version isn't known,
+// in which case the generated code is left as it is written in the interface.
+type goVersion int
+
+// parseGoVersion parses a Go version, e.g. `1.18`, `1.21.3` or `go1.22`. An empty string is an unknown version.
+func parseGoVersion(version string) (goVersion, error) {
+	if version == "" {
+		return 0, nil
+	}
+
+	matches := goVersionRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid Go version %q, expected a version like 1.18", version)
+	}
+
+	minor, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid Go version %q: %s", version, err)
+	}
+
+	return goVersion(minor), nil
+}
+
+func (v goVersion) String() string {
+	return fmt.Sprintf("1.%d", int(v))
+}
+
+func (v goVersion) isKnown() bool {
+	return v != 0
+}
+
+// needsPlusBuildLines returns true if `// +build` lines are needed alongside `//go:build` lines
+func (v goVersion) needsPlusBuildLines() bool {
+	return !v.isKnown() || v < goVersionGoBuildLines
+}
+
+// checkTypeData returns an error if the interface uses features that the Go version doesn't have
+func (v goVersion) checkTypeData(interfaceName string, typeData *TypeData) error {
+	if v.isKnown() && v < goVersionGenerics && len(typeData.TypeParams) != 0 {
+		return fmt.Errorf("%s is generic, which needs Go %s or later, but the mock is generated for Go %s. Set the Go version with the go directive in go.mod or --go-version", interfaceName, goVersion(goVersionGenerics), v)
+	}
+
+	return nil
+}
+
+// rewriteEmptyInterfaces writes empty interfaces as `any` for Go versions that have it, and `any` as `interface{}` for older versions.
+// Type expressions are left as they are if the version isn't known.
+func (v goVersion) rewriteEmptyInterfaces(typeExpr string) (string, error) {
+	if !v.isKnown() {
+		return typeExpr, nil
+	}
+
+	fileSet := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fileSet, "", typeExpr, 0)
+	if err != nil {
+		return "", fmt.Errorf("couldn't parse type %q: %s", typeExpr, err)
+	}
+	offsetOf := func(pos token.Pos) int {
+		return fileSet.Position(pos).Offset
+	}
+
+	type replacement struct {
+		start, end int
+		text       string
+	}
+	var replacements []replacement
+	if v >= goVersionGenerics {
+		ast.Inspect(expr, func(node ast.Node) bool {
+			interfaceType, ok := node.(*ast.InterfaceType)
+			if ok && len(interfaceType.Methods.List) == 0 {
+				replacements = append(replacements, replacement{offsetOf(interfaceType.Pos()), offsetOf(interfaceType.End()), "any"})
+			}
+			return true
+		})
+	} else {
+		visitTypeIdents(expr, func(ident *ast.Ident) {
+			if ident.Name == "any" {
+				replacements = append(replacements, replacement{offsetOf(ident.Pos()), offsetOf(ident.End()), "interface{}"})
+			}
+		})
+	}
+
+	// replace from the end, so the offsets of the earlier replacements stay valid
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+	for _, r := range replacements {
+		typeExpr = typeExpr[:r.start] + r.text + typeExpr[r.end:]
+	}
+
+	return typeExpr, nil
+}
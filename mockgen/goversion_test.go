@@ -0,0 +1,183 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseGoVersion(t *testing.T) {
+	tests := map[string]goVersion{
+		"":          0,
+		"1.16":      16,
+		"1.21.3":    21,
+		"go1.22":    22,
+		"go1.23rc1": 23,
+	}
+	for version, expected := range tests {
+		parsed, err := parseGoVersion(version)
+		require.NoError(t, err, version)
+		assert.Equal(t, expected, parsed, version)
+	}
+
+	_, err := parseGoVersion("2.0")
+	assert.Error(t, err)
+}
+
+func Test_goVersion_rewriteEmptyInterfaces(t *testing.T) {
+	tests := []struct {
+		version  goVersion
+		typeExpr string
+		want     string
+	}{
+		{0, "map[string]interface{}", "map[string]interface{}"},
+		{18, "map[string]interface{}", "map[string]any"},
+		{18, "func(args ...interface{}) interface{ Close() error }", "func(args ...any) interface{ Close() error }"},
+		{16, "func(args ...any) []any", "func(args ...interface{}) []interface{}"},
+		{16, "func(any int) extrapkg.Error", "func(any int) extrapkg.Error"},
+	}
+	for _, tt := range tests {
+		rewritten, err := tt.version.rewriteEmptyInterfaces(tt.typeExpr)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, rewritten, "%s for Go %s", tt.typeExpr, tt.version)
+	}
+}
+
+const genericTestSource = `package example
+
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V, tags ...interface{})
+	Getter[V]
+}
+`
+
+func TestWriteMockType_generic(t *testing.T) {
+	typeData, err := GetMethodsForType(genericTestSource, "Cache")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Cache", typeData, Options{GoVersion: "1.18"})
+	require.NoError(t, err)
+
+	expected := `// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.
+
+package example
+
+import (
+	"sync"
+)
+
+// MockCache is a mock implementation of [Cache].
+type MockCache[K comparable, V any] struct {
+	// GetFunc is called by [MockCache.Get].
+	GetFunc func(key K) (V, bool)
+	// SetFunc is called by [MockCache.Set].
+	SetFunc func(key K, value V, tags ...any)
+	Getter[V]
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Get []MockCacheGetCall[K, V]
+		Set []MockCacheSetCall[K, V]
+	}
+}
+
+// compile time checks that *MockCache implements the interface and the interfaces it embeds
+func _[K comparable, V any]() {
+	var _ Cache[K, V] = &MockCache[K, V]{}
+	var _ Getter[V] = &MockCache[K, V]{}
+}
+
+// Get implements [Cache.Get] by calling GetFunc.
+func (o *MockCache[K, V]) Get(key K) (V, bool) {
+	o.mockMu.Lock()
+	o.mockCalls.Get = append(o.mockCalls.Get, MockCacheGetCall[K, V]{Key: key})
+	o.mockMu.Unlock()
+
+	if o.GetFunc == nil {
+		panic("GetFunc not defined")
+	}
+	return o.GetFunc(key)
+}
+
+// MockCacheGetCall is a call to [MockCache.Get], with its arguments.
+type MockCacheGetCall[K comparable, V any] struct {
+	Key K
+}
+
+// GetCalls returns the calls to [MockCache.Get], in the order they were made.
+func (o *MockCache[K, V]) GetCalls() []MockCacheGetCall[K, V] {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockCacheGetCall[K, V](nil), o.mockCalls.Get...)
+}
+
+// GetCallCount returns the number of calls to [MockCache.Get].
+func (o *MockCache[K, V]) GetCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.Get)
+}
+
+// Set implements [Cache.Set] by calling SetFunc.
+func (o *MockCache[K, V]) Set(key K, value V, tags ...any) {
+	o.mockMu.Lock()
+	o.mockCalls.Set = append(o.mockCalls.Set, MockCacheSetCall[K, V]{Key: key, Value: value, Tags: tags})
+	o.mockMu.Unlock()
+
+	if o.SetFunc == nil {
+		panic("SetFunc not defined")
+	}
+	o.SetFunc(key, value, tags...)
+}
+
+// MockCacheSetCall is a call to [MockCache.Set], with its arguments.
+type MockCacheSetCall[K comparable, V any] struct {
+	Key   K
+	Value V
+	Tags  []any
+}
+
+// SetCalls returns the calls to [MockCache.Set], in the order they were made.
+func (o *MockCache[K, V]) SetCalls() []MockCacheSetCall[K, V] {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockCacheSetCall[K, V](nil), o.mockCalls.Set...)
+}
+
+// SetCallCount returns the number of calls to [MockCache.Set].
+func (o *MockCache[K, V]) SetCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.Set)
+}
+`
+	assert.Equal(t, expected, mockText)
+
+	t.Run("out of package", func(t *testing.T) {
+		mockText, err := WriteMockType("Cache", typeData, Options{GoVersion: "1.18", OutPackage: "mocks", PackageImportPath: "example.com/example"})
+		require.NoError(t, err)
+
+		assert.Contains(t, mockText, "\tvar _ example.Cache[K, V] = &MockCache[K, V]{}\n\tvar _ example.Getter[V] = &MockCache[K, V]{}\n")
+	})
+
+	t.Run("Go version without generics", func(t *testing.T) {
+		_, err := WriteMockType("Cache", typeData, Options{GoVersion: "1.17"})
+		assert.EqualError(t, err, "Cache is generic, which needs Go 1.18 or later, but the mock is generated for Go 1.17. Set the Go version with the go directive in go.mod or --go-version")
+	})
+}
+
+func TestWriteMockType_goVersionBuildConstraints(t *testing.T) {
+	typeData, err := GetMethodsForType("package example\ntype Vehicle interface {\n\tName() string\n}\n", "Vehicle")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Vehicle", typeData, Options{BuildConstraint: "testmocks", GoVersion: "1.16"})
+	require.NoError(t, err)
+	assert.Contains(t, mockText, "//go:build testmocks\n// +build testmocks\n")
+
+	mockText, err = WriteMockType("Vehicle", typeData, Options{BuildConstraint: "testmocks", GoVersion: "1.17"})
+	require.NoError(t, err)
+	assert.Contains(t, mockText, "//go:build testmocks\n\npackage example")
+}
@@ -0,0 +1,184 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// knownOS and knownArch are the GOOS and GOARCH values recognised in file names, e.g. `vehicle_linux.go`
+var (
+	knownOS = toSet(
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js", "linux",
+		"nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	)
+	knownArch = toSet(
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64", "mips", "mipsle",
+		"mips64", "mips64le", "mips64p32", "mips64p32le", "ppc", "ppc64", "ppc64le", "riscv", "riscv64",
+		"s390", "s390x", "sparc", "sparc64", "wasm",
+	)
+)
+
+func toSet(values ...string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
+
+// fileBuildConstraint returns the build constraint of a source file, made up of its //go:build (or // +build) lines
+// and the GOOS/GOARCH in its file name. It returns nil if the file is not constrained.
+func fileBuildConstraint(filename string, parsedFile *ast.File) (constraint.Expr, error) {
+	var goBuildExpr, plusBuildExpr constraint.Expr
+	for _, commentGroup := range parsedFile.Comments {
+		if commentGroup.Pos() >= parsedFile.Package {
+			// build constraints must come before the package clause
+			break
+		}
+		for _, comment := range commentGroup.List {
+			switch {
+			case constraint.IsGoBuild(comment.Text):
+				expr, err := constraint.Parse(comment.Text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid build constraint %q: %s", comment.Text, err)
+				}
+				goBuildExpr = expr
+			case constraint.IsPlusBuild(comment.Text):
+				expr, err := constraint.Parse(comment.Text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid build constraint %q: %s", comment.Text, err)
+				}
+				// multiple // +build lines must all be satisfied
+				plusBuildExpr = andBuildConstraints(plusBuildExpr, expr)
+			}
+		}
+	}
+
+	expr := goBuildExpr
+	if expr == nil {
+		expr = plusBuildExpr
+	}
+
+	return andBuildConstraints(fileNameBuildConstraint(filename), expr), nil
+}
+
+// fileNameBuildConstraint returns the constraint implied by a file name, e.g. `linux` for `vehicle_linux.go`, following the rules of go/build.
+// It returns nil if the file name has no GOOS or GOARCH suffix.
+func fileNameBuildConstraint(filename string) constraint.Expr {
+	name := strings.TrimSuffix(filepath.Base(filename), ".go")
+	underscoreIdx := strings.Index(name, "_")
+	if underscoreIdx == -1 {
+		return nil
+	}
+
+	fragments := strings.Split(name[underscoreIdx:], "_")
+	if fragments[len(fragments)-1] == "test" {
+		fragments = fragments[:len(fragments)-1]
+	}
+
+	fragmentsLen := len(fragments)
+	if fragmentsLen >= 2 {
+		_, isOS := knownOS[fragments[fragmentsLen-2]]
+		_, isArch := knownArch[fragments[fragmentsLen-1]]
+		if isOS && isArch {
+			return &constraint.AndExpr{
+				X: &constraint.TagExpr{Tag: fragments[fragmentsLen-2]},
+				Y: &constraint.TagExpr{Tag: fragments[fragmentsLen-1]},
+			}
+		}
+	}
+
+	if fragmentsLen >= 1 {
+		last := fragments[fragmentsLen-1]
+		_, isOS := knownOS[last]
+		_, isArch := knownArch[last]
+		if isOS || isArch {
+			return &constraint.TagExpr{Tag: last}
+		}
+	}
+
+	return nil
+}
+
+// parseBuildConstraint parses a build constraint expression, e.g. `testmocks && !windows`. The `//go:build` prefix is optional.
+func parseBuildConstraint(text string) (constraint.Expr, error) {
+	text = strings.TrimSpace(text)
+	if !constraint.IsGoBuild(text) {
+		text = "//go:build " + text
+	}
+
+	expr, err := constraint.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid build constraint %q: %s", text, err)
+	}
+
+	return expr, nil
+}
+
+// andBuildConstraints combines two build constraints, either of which may be nil
+func andBuildConstraints(x, y constraint.Expr) constraint.Expr {
+	if x == nil {
+		return y
+	}
+	if y == nil {
+		return x
+	}
+
+	return &constraint.AndExpr{X: x, Y: y}
+}
+
+// buildConstraintLines returns the `//go:build` line for the constraint, along with the equivalent `// +build` lines if withPlusBuildLines is set
+func buildConstraintLines(expr constraint.Expr, withPlusBuildLines bool) ([]string, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	if !withPlusBuildLines {
+		return []string{"//go:build " + expr.String()}, nil
+	}
+
+	plusBuildLines, err := constraint.PlusBuildLines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{"//go:build " + expr.String()}, plusBuildLines...), nil
+}
+
+// commentText turns text into Go comments, unless it is already made up of comments
+func commentText(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(text, "/*") && strings.HasSuffix(text, "*/") {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	isAllComments := true
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "//") {
+			isAllComments = false
+			break
+		}
+	}
+	if isAllComments {
+		return text
+	}
+
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			lines[i] = "//"
+			continue
+		}
+		lines[i] = "// " + line
+	}
+
+	return strings.Join(lines, "\n")
+}
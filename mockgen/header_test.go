@@ -0,0 +1,70 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fileNameBuildConstraint(t *testing.T) {
+	tests := map[string]string{
+		"vehicle.go":                   "",
+		"vehicle_linux.go":             "linux",
+		"vehicle_linux_test.go":        "linux",
+		"vehicle_amd64.go":             "amd64",
+		"path/to/vehicle_linux_arm.go": "linux && arm",
+		"linux.go":                     "",
+		"vehicle_mock.go":              "",
+	}
+	for filename, expected := range tests {
+		expr := fileNameBuildConstraint(filename)
+		if expected == "" {
+			assert.Nil(t, expr, filename)
+			continue
+		}
+		require.NotNil(t, expr, filename)
+		assert.Equal(t, expected, expr.String(), filename)
+	}
+}
+
+func Test_commentText(t *testing.T) {
+	assert.Equal(t, "", commentText("  \n"))
+	assert.Equal(t, "// Copyright 2021 Example\n//\n// Licensed under MIT", commentText("Copyright 2021 Example\n\nLicensed under MIT\n"))
+	assert.Equal(t, "// Copyright 2021 Example", commentText("// Copyright 2021 Example\n"))
+	assert.Equal(t, "/*\nCopyright 2021 Example\n*/", commentText("/*\nCopyright 2021 Example\n*/"))
+}
+
+func TestWriteMockType_headerAndBuildConstraints(t *testing.T) {
+	sourceCode := `// +build !windows
+// +build cgo
+
+package example
+
+type Vehicle interface {
+	Name() string
+}
+`
+	typeData, err := getMethodsForType("vehicle_linux.go", sourceCode, "Vehicle")
+	require.NoError(t, err)
+	assert.Equal(t, "linux && !windows && cgo", typeData.BuildConstraint)
+
+	mockText, err := WriteMockType("Vehicle", typeData, Options{
+		Header:          "Copyright 2021 Example",
+		BuildConstraint: "testmocks",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `// Copyright 2021 Example
+
+// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.
+
+//go:build linux && !windows && cgo && testmocks
+// +build linux,!windows,cgo,testmocks
+
+package example
+`)
+
+	_, err = WriteMockType("Vehicle", typeData, Options{BuildConstraint: "testmocks &&"})
+	require.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package mockgen
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InspectSchemaVersion is the version of the JSON document produced by Inspect.
+// It is incremented whenever a field is changed or removed; new fields can be added without changing the version.
+const InspectSchemaVersion = 1
+
+// InterfaceDescription is the JSON representation of a resolved interface type, for use by other tools
+type InterfaceDescription struct {
+	SchemaVersion      int                 `json:"schemaVersion"`
+	Name               string              `json:"name"`
+	PackageName        string              `json:"packageName"`
+	Doc                string              `json:"doc,omitempty"`
+	Position           PositionDescription `json:"position"`
+	Methods            []MethodDescription `json:"methods"`
+	EmbeddedInterfaces []string            `json:"embeddedInterfaces"`
+	Imports            []ImportDescription `json:"imports"`
+	BuildConstraint    string              `json:"buildConstraint,omitempty"`
+	// FlattenedInterfaces are the embedded interfaces whose methods are included in Methods, with --flatten
+	FlattenedInterfaces []string `json:"flattenedInterfaces,omitempty"`
+	// TypeParams are the type parameters of a generic interface, with their constraints as the types
+	TypeParams []TypeDescription `json:"typeParams,omitempty"`
+	// Interfaces are the interfaces combined into one, when several are given
+	Interfaces []string `json:"interfaces,omitempty"`
+	// Func is true for named function types, which are described as an interface with a single Call method
+	Func bool `json:"func,omitempty"`
+}
+
+type MethodDescription struct {
+	Name     string              `json:"name"`
+	Doc      string              `json:"doc,omitempty"`
+	Position PositionDescription `json:"position"`
+	Params   []TypeDescription   `json:"params"`
+	Results  []TypeDescription   `json:"results"`
+	Variadic bool                `json:"variadic"`
+	// Annotations are set with `//mockgen:` comment directives on the method
+	Annotations *MethodAnnotations `json:"annotations,omitempty"`
+	// Interface is the embedded interface the method is from, with --flatten
+	Interface string `json:"interface,omitempty"`
+}
+
+type TypeDescription struct {
+	// Name is the name of the parameter or result. Empty if it is unnamed in the interface declaration.
+	Name string `json:"name,omitempty"`
+	// Type is the type as written in the declaration, e.g. `extrapkg.Error`. For variadic parameters, it is the element type.
+	Type        string `json:"type"`
+	PackageName string `json:"packageName,omitempty"`
+	Variadic    bool   `json:"variadic,omitempty"`
+}
+
+type PositionDescription struct {
+	Filename string `json:"filename,omitempty"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+type ImportDescription struct {
+	// Name is the alias the package is imported with, if any
+	Name string `json:"name,omitempty"`
+	Path string `json:"path"`
+}
+
+// Inspect converts the type data for an interface to its JSON representation
+func Inspect(interfaceName string, typeData *TypeData) InterfaceDescription {
+	description := InterfaceDescription{
+		SchemaVersion:      InspectSchemaVersion,
+		Name:               interfaceName,
+		PackageName:        typeData.PackageName,
+		Doc:                strings.TrimSpace(typeData.Doc),
+		Position:           newPositionDescription(typeData.Position),
+		Methods:            []MethodDescription{},
+		EmbeddedInterfaces: []string{},
+		Imports:            []ImportDescription{},
+		BuildConstraint:    typeData.BuildConstraint,
+	}
+	if len(typeData.TypeParams) != 0 {
+		description.TypeParams = newTypeDescriptions(typeData.TypeParams)
+	}
+
+	for _, method := range typeData.Methods {
+		methodDescription := MethodDescription{
+			Name:      method.Name,
+			Doc:       strings.TrimSpace(method.Doc),
+			Position:  newPositionDescription(method.Position),
+			Params:    newTypeDescriptions(method.Params),
+			Results:   newTypeDescriptions(method.ReturnTypes),
+			Interface: method.Interface,
+		}
+		if len(method.Params) != 0 {
+			methodDescription.Variadic = method.Params[len(method.Params)-1].Variadic
+		}
+		if method.Annotations.Skip || len(method.Annotations.DefaultReturn) != 0 || method.Annotations.FieldName != "" {
+			annotations := method.Annotations
+			methodDescription.Annotations = &annotations
+		}
+		description.Methods = append(description.Methods, methodDescription)
+	}
+
+	description.EmbeddedInterfaces = append(description.EmbeddedInterfaces, typeData.EmbeddedInterfaces...)
+	description.FlattenedInterfaces = typeData.FlattenedInterfaces
+	description.Interfaces = typeData.Interfaces
+	description.Func = typeData.Func
+
+	for _, im := range typeData.Imports {
+		importDescription := ImportDescription{}
+		if im.Name != nil {
+			importDescription.Name = im.Name.Name
+		}
+		importDescription.Path, _ = strconv.Unquote(im.Path.Value)
+		description.Imports = append(description.Imports, importDescription)
+	}
+
+	return description
+}
+
+func newTypeDescriptions(types []Type) []TypeDescription {
+	descriptions := []TypeDescription{}
+	for _, t := range types {
+		descriptions = append(descriptions, TypeDescription{
+			Name:        t.Name,
+			Type:        t.FullTypeName(),
+			PackageName: t.PackageName,
+			Variadic:    t.Variadic,
+		})
+	}
+	return descriptions
+}
+
+func newPositionDescription(position Position) PositionDescription {
+	return PositionDescription{
+		Filename: position.Filename,
+		Offset:   position.Offset,
+		Line:     position.Line,
+		Column:   position.Column,
+	}
+}
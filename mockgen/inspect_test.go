@@ -0,0 +1,61 @@
+package mockgen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	sourceCode := `package example
+
+import (
+	"io"
+	osfs "os"
+)
+
+// Store stores things
+type Store interface {
+	// Put puts things
+	Put(key string, values ...osfs.FileInfo) (n int, err error)
+	io.Closer
+}
+`
+	typeData, err := GetMethodsForType(sourceCode, "Store")
+	require.NoError(t, err)
+
+	description := Inspect("Store", typeData)
+
+	expectedJSON := `{
+	"schemaVersion": 1,
+	"name": "Store",
+	"packageName": "example",
+	"doc": "Store stores things",
+	"position": {"offset": 80, "line": 9, "column": 12},
+	"methods": [{
+		"name": "Put",
+		"doc": "Put puts things",
+		"position": {"offset": 113, "line": 11, "column": 2},
+		"params": [
+			{"name": "key", "type": "string"},
+			{"name": "values", "type": "osfs.FileInfo", "packageName": "osfs", "variadic": true}
+		],
+		"results": [
+			{"name": "n", "type": "int"},
+			{"name": "err", "type": "error"}
+		],
+		"variadic": true
+	}],
+	"embeddedInterfaces": ["io.Closer"],
+	"imports": [
+		{"path": "io"},
+		{"name": "osfs", "path": "os"}
+	]
+}`
+
+	descriptionJSON, err := json.Marshal(description)
+	require.NoError(t, err)
+	assert.JSONEq(t, expectedJSON, string(descriptionJSON))
+}
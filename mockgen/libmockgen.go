@@ -6,6 +6,8 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/ioutil"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -14,15 +16,75 @@ var ErrInterfaceTypeNotFound = errors.New("interface type not found")
 
 const internalFuncSuffix = "Func"
 
+// funcTypeMethodName is the name of the mock's method with the signature of the function, for mocks of function types.
+// The default template refers to it by name.
+const funcTypeMethodName = "Call"
+
 type TypeData struct {
 	PackageName        string
 	Imports            []*ast.ImportSpec
 	Methods            []Method
 	EmbeddedInterfaces []string
+	// Doc is the doc comment of the interface type declaration, if any
+	Doc string
+	// Position is the position of the interface type in the source file
+	Position Position
+	// BuildConstraint is the build constraint of the file the interface is in, e.g. `linux && !cgo`. Empty if the file is not constrained.
+	BuildConstraint string
+	// Literal is the source of the interface type, e.g. `interface{ Close() error }`, if it is an anonymous interface rather than a declared type
+	Literal string
+	// TypeParams are the type parameters of a generic interface, with their constraints as the type names, e.g. `K comparable`
+	TypeParams []Type
+	// FlattenedInterfaces are the embedded interfaces whose methods have been added to Methods, see FlattenEmbeddedInterfaces
+	FlattenedInterfaces []string
+	// Interfaces are the interfaces combined by CompositeTypeData, e.g. `Store` and `io.Closer`. Empty for single interfaces.
+	Interfaces []string
+	// Func is true for named function types, e.g. `type Handler func(req Request) error`, rather than interfaces.
+	// They have a single method, funcTypeMethodName, with the function's signature.
+	Func bool
+}
+
+// TypeParamsDecl returns the type parameter list of a generic interface, e.g. `[K comparable, V any]`. It is empty if the interface isn't generic.
+func (typeData *TypeData) TypeParamsDecl() string {
+	if len(typeData.TypeParams) == 0 {
+		return ""
+	}
+
+	var fragments []string
+	for _, typeParam := range typeData.TypeParams {
+		fragments = append(fragments, fmt.Sprintf("%s %s", typeParam.Name, typeParam.FullTypeName()))
+	}
+
+	return "[" + strings.Join(fragments, ", ") + "]"
+}
+
+// TypeArgs returns the type parameters of a generic interface as type arguments, e.g. `[K, V]`. It is empty if the interface isn't generic.
+func (typeData *TypeData) TypeArgs() string {
+	if len(typeData.TypeParams) == 0 {
+		return ""
+	}
+
+	var names []string
+	for _, typeParam := range typeData.TypeParams {
+		names = append(names, typeParam.Name)
+	}
+
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// typeParamNames returns the names of the type parameters, which refer to types that aren't declared in a package
+func (typeData *TypeData) typeParamNames() map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, typeParam := range typeData.TypeParams {
+		names[typeParam.Name] = struct{}{}
+	}
+	return names
 }
 
 type Type struct {
 	PackageName, TypeName, Name string
+	// Variadic is true for the last parameter of a variadic function, e.g. `args ...int`. TypeName does not contain the "..." prefix.
+	Variadic bool
 }
 
 func (t Type) FullTypeName() string {
@@ -37,6 +99,15 @@ type Method struct {
 	Name        string
 	Params      []Type
 	ReturnTypes []Type
+	// Doc is the doc comment of the method in the interface declaration, if any
+	Doc string
+	// Position is the position of the method name in the source file
+	Position Position
+	// Annotations are set with `//mockgen:` comment directives on the method
+	Annotations MethodAnnotations
+	// Interface is the embedded interface the method was added from by FlattenEmbeddedInterfaces, e.g. `io.Closer`.
+	// Empty for the methods declared in the interface itself.
+	Interface string
 }
 
 func (method Method) ParamNames() []string {
@@ -59,7 +130,7 @@ func (method Method) ParamsWithTypes() string {
 		if paramName == "" {
 			paramName = fmt.Sprintf("param%d", i)
 		}
-		fullParamFragments = append(fullParamFragments, fmt.Sprintf("%s %s", paramName, param.FullTypeName()))
+		fullParamFragments = append(fullParamFragments, fmt.Sprintf("%s %s", paramName, param.paramTypeName()))
 	}
 
 	return strings.Join(fullParamFragments, ", ")
@@ -84,133 +155,327 @@ func (method Method) ReturnTypesAsString() string {
 	return retSignature
 }
 
+// paramTypeName is the type name as written in a parameter list, i.e. with the "..." prefix for variadic parameters
+func (t Type) paramTypeName() string {
+	if t.Variadic {
+		return "..." + t.FullTypeName()
+	}
+
+	return t.FullTypeName()
+}
+
+// GetMethodsForTypeInDir looks through the Go files in a directory for the interface type.
+// ErrInterfaceTypeNotFound is returned if none of the files contain it.
+func GetMethodsForTypeInDir(dirPath, interfaceName string) (*TypeData, error) {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %q", err)
+	}
+
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), ".go") {
+			// skip non Go files
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, fileInfo.Name())
+		sourceCode, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read %q. Error: %q", filePath, err)
+		}
+
+		typeData, err := getMethodsForType(filePath, string(sourceCode), interfaceName)
+		if err != nil {
+			if err != ErrInterfaceTypeNotFound {
+				return nil, err
+			}
+			// continue with other files
+			continue
+		}
+
+		return typeData, nil
+	}
+
+	return nil, ErrInterfaceTypeNotFound
+}
+
 func GetMethodsForType(sourceCode, interfaceName string) (*TypeData, error) {
-	parsedFile, err := parser.ParseFile(token.NewFileSet(), "", sourceCode, 0)
+	return getMethodsForType("", sourceCode, interfaceName)
+}
+
+func getMethodsForType(filename, sourceCode, interfaceName string) (*TypeData, error) {
+	fileSet := token.NewFileSet()
+	parsedFile, err := parser.ParseFile(fileSet, filename, sourceCode, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
 
-	var itemNameFound bool
-	var allDone bool
+	var foundTypeSpec *ast.TypeSpec
+	ast.Inspect(parsedFile, func(node ast.Node) bool {
+		if foundTypeSpec != nil {
+			return false
+		}
+		typeSpec, ok := node.(*ast.TypeSpec)
+		if !ok || typeSpec.Name.Name != interfaceName {
+			return true
+		}
+		foundTypeSpec = typeSpec
+		return false
+	})
+
+	if foundTypeSpec == nil {
+		return nil, ErrInterfaceTypeNotFound
+	}
 
+	switch t := foundTypeSpec.Type.(type) {
+	case *ast.InterfaceType:
+		return getTypeDataForInterface(fileSet, sourceCode, parsedFile, t)
+	case *ast.FuncType:
+		return getTypeDataForFunc(fileSet, sourceCode, parsedFile, foundTypeSpec, t)
+	default:
+		return nil, ErrInterfaceTypeNotFound
+	}
+}
+
+// getTypeDataForInterface collects the methods, embedded interfaces and required imports of an interface type in a parsed file.
+// interfaceType may be nil, in which case only the package name is filled in.
+func getTypeDataForInterface(fileSet *token.FileSet, sourceCode string, parsedFile *ast.File, interfaceType *ast.InterfaceType) (*TypeData, error) {
 	typeData := &TypeData{
 		PackageName: parsedFile.Name.Name,
 		Methods:     nil,
 	}
 
+	buildConstraint, err := fileBuildConstraint(fileSet.File(parsedFile.Pos()).Name(), parsedFile)
+	if err != nil {
+		return nil, err
+	}
+	if buildConstraint != nil {
+		typeData.BuildConstraint = buildConstraint.String()
+	}
+
 	importPathShortNames := make(map[string]struct{})
 
-	ast.Inspect(parsedFile, func(node ast.Node) bool {
-		if allDone {
-			return false
+	if interfaceType != nil {
+		typeData.Doc = findDocForType(parsedFile, interfaceType).Text()
+		typeData.Position = newPosition(fileSet, interfaceType.Pos())
+		typeSpec := findTypeSpecForInterface(parsedFile, interfaceType)
+		if typeSpec == nil {
+			typeData.Literal = getNameForAstNode(sourceCode, interfaceType)
+		} else {
+			typeData.TypeParams = getTypeParams(sourceCode, typeSpec, importPathShortNames)
 		}
-		switch n := node.(type) {
-		case *ast.Ident:
-			if n.Name == interfaceName {
-				itemNameFound = true
-			}
-		case *ast.InterfaceType:
-			if itemNameFound == false {
-				// not the interface we are looking for, skip
-				return false
-			}
 
-			for _, astField := range n.Methods.List {
-				var names []string
-				switch astFieldType := astField.Type.(type) {
-				case *ast.SelectorExpr:
-					// embedded interfaces in other packages, e.g. `type X interface {io.Reader}`
-					importPathName := astFieldType.X.(*ast.Ident).Name
-					if importPathName != "" {
-						importPathShortNames[importPathName] = struct{}{}
-					}
-					name := getNameForAstNode(sourceCode, astFieldType)
-					typeData.EmbeddedInterfaces = append(typeData.EmbeddedInterfaces, name)
-				case *ast.Ident:
-					// embedded interfaces in the same package
-					typeData.EmbeddedInterfaces = append(typeData.EmbeddedInterfaces, astFieldType.Name)
-				case *ast.FuncType:
-					// functions defined on the interface
-					for _, name := range astField.Names {
-						names = append(names, name.String())
-					}
-
-					var paramTypes, returnTypes []Type
-
-					switch t := astField.Type.(type) {
-					case *ast.FuncType:
-						for _, param := range t.Params.List {
-							paramNameText := getNameForAstNode(sourceCode, param)
-							paramTypesInMethod := getTypesFromText(paramNameText)
-
-							for _, paramType := range paramTypesInMethod {
-								if paramType.PackageName != "" {
-									importPathShortNames[paramType.PackageName] = struct{}{}
-								}
-							}
-
-							paramTypes = append(paramTypes, paramTypesInMethod...)
-						}
-						if t.Results != nil {
-							retText := getNameForAstNode(sourceCode, t.Results)
-							if strings.HasPrefix(retText, "(") {
-								retText = strings.TrimPrefix(retText, "(")
-								retText = strings.TrimSuffix(retText, ")")
-							}
-							returnTypesFromMethods := getTypesFromText(retText)
-
-							for _, returnType := range returnTypesFromMethods {
-								if returnType.PackageName != "" {
-									importPathShortNames[returnType.PackageName] = struct{}{}
-								}
-							}
-
-							returnTypes = append(returnTypes, returnTypesFromMethods...)
-						}
-					}
-
-					for _, name := range names {
-						typeData.Methods = append(
-							typeData.Methods,
-							Method{
-								name,
-								paramTypes,
-								returnTypes,
-							},
-						)
-
-					}
+		for _, astField := range interfaceType.Methods.List {
+			switch astFieldType := astField.Type.(type) {
+			case *ast.SelectorExpr:
+				// embedded interfaces in other packages, e.g. `type X interface {io.Reader}`
+				importPathName := astFieldType.X.(*ast.Ident).Name
+				if importPathName != "" {
+					importPathShortNames[importPathName] = struct{}{}
+				}
+				name := getNameForAstNode(sourceCode, astFieldType)
+				typeData.EmbeddedInterfaces = append(typeData.EmbeddedInterfaces, name)
+			case *ast.Ident:
+				// embedded interfaces in the same package
+				typeData.EmbeddedInterfaces = append(typeData.EmbeddedInterfaces, astFieldType.Name)
+			case *ast.IndexExpr, *ast.IndexListExpr:
+				// embedded instantiated generic interfaces, e.g. `type X[T any] interface {Getter[T]}`
+				name := getNameForAstNode(sourceCode, astFieldType)
+				for _, packageName := range packagesInTypeExpr(name) {
+					importPathShortNames[packageName] = struct{}{}
+				}
+				typeData.EmbeddedInterfaces = append(typeData.EmbeddedInterfaces, name)
+			case *ast.FuncType:
+				// functions defined on the interface
+				paramTypes, returnTypes := getSignatureTypes(sourceCode, astFieldType, importPathShortNames)
+
+				annotations, packageNames, err := parseMethodAnnotations(astField.Doc, len(returnTypes))
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s", newPosition(fileSet, astField.Pos()), err)
+				}
+				for _, packageName := range packageNames {
+					importPathShortNames[packageName] = struct{}{}
+				}
+
+				for _, name := range astField.Names {
+					typeData.Methods = append(
+						typeData.Methods,
+						Method{
+							Name:        name.String(),
+							Params:      paramTypes,
+							ReturnTypes: returnTypes,
+							Doc:         astField.Doc.Text(),
+							Position:    newPosition(fileSet, name.Pos()),
+							Annotations: annotations,
+						},
+					)
 				}
 			}
+		}
+	}
 
-			allDone = true
-			return false
+	typeData.Imports = requiredImports(parsedFile, importPathShortNames)
+
+	return typeData, nil
+}
+
+// getTypeDataForFunc collects the signature and required imports of a named function type, e.g. `type Handler func(req Request) error`.
+// The function type is described as an interface with a single method, funcTypeMethodName, with the function's signature.
+func getTypeDataForFunc(fileSet *token.FileSet, sourceCode string, parsedFile *ast.File, typeSpec *ast.TypeSpec, funcType *ast.FuncType) (*TypeData, error) {
+	// only fills in the package name and build constraint
+	typeData, err := getTypeDataForInterface(fileSet, sourceCode, parsedFile, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	importPathShortNames := make(map[string]struct{})
+
+	typeData.Func = true
+	typeData.Doc = findDocForType(parsedFile, funcType).Text()
+	typeData.Position = newPosition(fileSet, funcType.Pos())
+	typeData.TypeParams = getTypeParams(sourceCode, typeSpec, importPathShortNames)
+
+	paramTypes, returnTypes := getSignatureTypes(sourceCode, funcType, importPathShortNames)
+	typeData.Methods = []Method{{
+		Name:        funcTypeMethodName,
+		Params:      paramTypes,
+		ReturnTypes: returnTypes,
+		Position:    newPosition(fileSet, typeSpec.Name.Pos()),
+	}}
+
+	typeData.Imports = requiredImports(parsedFile, importPathShortNames)
+
+	return typeData, nil
+}
+
+// getTypeParams returns the type parameters of a generic type declaration, adding the packages referred to by their constraints to importPathShortNames
+func getTypeParams(sourceCode string, typeSpec *ast.TypeSpec, importPathShortNames map[string]struct{}) []Type {
+	if typeSpec.TypeParams == nil {
+		return nil
+	}
+
+	var typeParams []Type
+	for _, field := range typeSpec.TypeParams.List {
+		constraintText := getNameForAstNode(sourceCode, field.Type)
+		for _, packageName := range packagesInTypeExpr(constraintText) {
+			importPathShortNames[packageName] = struct{}{}
+		}
+		for _, name := range field.Names {
+			typeParam := fullTypeToType(constraintText)
+			typeParam.Name = name.Name
+			typeParams = append(typeParams, typeParam)
 		}
+	}
 
-		return true
-	})
+	return typeParams
+}
 
-	if !itemNameFound {
-		return nil, ErrInterfaceTypeNotFound
+// getSignatureTypes returns the parameter and result types of a function type, adding the packages referred to by them to importPathShortNames
+func getSignatureTypes(sourceCode string, funcType *ast.FuncType, importPathShortNames map[string]struct{}) ([]Type, []Type) {
+	var paramTypes, returnTypes []Type
+
+	for _, param := range funcType.Params.List {
+		paramNameText := getNameForAstNode(sourceCode, param)
+		paramTypesInMethod := getTypesFromText(paramNameText)
+
+		for _, paramType := range paramTypesInMethod {
+			for _, packageName := range packagesInTypeExpr(paramType.FullTypeName()) {
+				importPathShortNames[packageName] = struct{}{}
+			}
+		}
+
+		paramTypes = append(paramTypes, paramTypesInMethod...)
 	}
+	if funcType.Results != nil {
+		retText := getNameForAstNode(sourceCode, funcType.Results)
+		if strings.HasPrefix(retText, "(") {
+			retText = strings.TrimPrefix(retText, "(")
+			retText = strings.TrimSuffix(retText, ")")
+		}
+		returnTypesFromMethods := getTypesFromText(retText)
 
-	// add imports
-	for _, im := range parsedFile.Imports {
-		pathFragments := strings.Split(im.Path.Value, "/")
-		shortName := pathFragments[len(pathFragments)-1]
-		if im.Name != nil {
-			shortName = im.Name.Name
+		for _, returnType := range returnTypesFromMethods {
+			for _, packageName := range packagesInTypeExpr(returnType.FullTypeName()) {
+				importPathShortNames[packageName] = struct{}{}
+			}
 		}
-		shortName = strings.Trim(shortName, `"`)
-		_, ok := importPathShortNames[shortName]
+
+		returnTypes = append(returnTypes, returnTypesFromMethods...)
+	}
+
+	return paramTypes, returnTypes
+}
+
+// requiredImports returns the imports of the file for the packages in importPathShortNames
+func requiredImports(parsedFile *ast.File, importPathShortNames map[string]struct{}) []*ast.ImportSpec {
+	var imports []*ast.ImportSpec
+	for _, im := range parsedFile.Imports {
+		_, ok := importPathShortNames[importShortName(im)]
 		if !ok {
 			// not required
 			continue
 		}
-		typeData.Imports = append(typeData.Imports, im)
+		imports = append(imports, im)
 	}
 
-	return typeData, nil
+	return imports
+}
+
+// importShortName returns the name a package is referred to by in the file, i.e. the alias it is imported with or the last element of its path
+func importShortName(im *ast.ImportSpec) string {
+	if im.Name != nil {
+		return im.Name.Name
+	}
+
+	pathFragments := strings.Split(im.Path.Value, "/")
+	return strings.Trim(pathFragments[len(pathFragments)-1], `"`)
+}
+
+// findDocForType finds the doc comment for the type declaration of the interface or function type, if it has one
+func findDocForType(parsedFile *ast.File, typeExpr ast.Expr) *ast.CommentGroup {
+	for _, decl := range parsedFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Type != typeExpr {
+				continue
+			}
+			if typeSpec.Doc == nil && len(genDecl.Specs) == 1 {
+				// the comment is attached to the declaration for `type X interface{}`, rather than `type ( X interface{} )`
+				return genDecl.Doc
+			}
+			return typeSpec.Doc
+		}
+	}
+
+	return nil
+}
+
+// findTypeSpecForInterface finds the type declaration of the interface type, e.g. `type X interface{}`.
+// It returns nil if the interface is an anonymous interface.
+func findTypeSpecForInterface(parsedFile *ast.File, interfaceType *ast.InterfaceType) *ast.TypeSpec {
+	var found *ast.TypeSpec
+	ast.Inspect(parsedFile, func(node ast.Node) bool {
+		if typeSpec, ok := node.(*ast.TypeSpec); ok && typeSpec.Type == interfaceType {
+			found = typeSpec
+		}
+		return found == nil
+	})
+
+	return found
+}
+
+func newPosition(fileSet *token.FileSet, pos token.Pos) Position {
+	position := fileSet.Position(pos)
+	return Position{
+		Filename: position.Filename,
+		Offset:   position.Offset,
+		Line:     position.Line,
+		Column:   position.Column,
+	}
 }
 
 type shortPackageNameMapType map[string]struct{}
@@ -228,18 +493,29 @@ func currentTokenToParamsObjects(text string) []string {
 		return []string{text}
 	}
 	paramName := strings.TrimSpace(text[:spaceIdx])
+	if _, isKeyword := typeKeywords[paramName]; isKeyword || !identifierRegex.MatchString(paramName) {
+		// an unnamed type with a space in it, e.g. `chan int` or `Pair[K, V]`
+		return []string{text}
+	}
 	typeName := strings.TrimSpace(text[spaceIdx:])
 
 	return []string{paramName, typeName}
 }
 
 func fullTypeToType(fullType string) Type {
+	if strings.HasPrefix(fullType, "...") {
+		t := fullTypeToType(strings.TrimPrefix(fullType, "..."))
+		t.Variadic = true
+		return t
+	}
+
 	if funcDefRegex.MatchString(fullType) {
 		return Type{TypeName: fullType}
 	}
 
 	dotIndex := strings.Index(fullType, ".")
-	if dotIndex == -1 {
+	if dotIndex == -1 || !identifierRegex.MatchString(fullType[:dotIndex]) {
+		// not a qualified name, e.g. `[]extrapkg.Error`
 		return Type{TypeName: fullType}
 	}
 
@@ -249,34 +525,38 @@ func fullTypeToType(fullType string) Type {
 	}
 }
 
+// typeKeywords are the keywords a type can start with
+var typeKeywords = toSet("chan", "func", "interface", "map", "struct")
+
 func parseParams(str string) [][]string {
 	currentToken := new(currentTokenType)
 	var paramsObjects [][]string
-	var funcNestingLevel int
+	var nestingLevel int
 
 	for _, c := range str {
 		switch c {
 		case ',':
-			if funcNestingLevel == 0 {
-				// if not 0, we are not finished with this yet. We either have no current token or we are in a function definition
+			if nestingLevel == 0 {
+				// if not 0, we are not finished with this yet. We either have no current token or we are in a function definition,
+				// type argument list or struct/interface literal
 				paramsObjects = append(paramsObjects, currentTokenToParamsObjects(currentToken.Token))
 
 				// reset
 				currentToken = new(currentTokenType)
 			}
-		case '(':
-			funcNestingLevel++
-		case ')':
-			funcNestingLevel--
+		case '(', '[', '{':
+			nestingLevel++
+		case ')', ']', '}':
+			nestingLevel--
 		}
 
-		if funcNestingLevel == 0 && c == ',' {
+		if nestingLevel == 0 && c == ',' {
 		} else {
 			currentToken.Token += string(c)
 		}
 	}
 
-	if funcNestingLevel > 0 {
+	if nestingLevel > 0 {
 		panic("there was an unclosed function in: " + str)
 	}
 
@@ -349,103 +629,117 @@ func reverseTypesSlice(in []Type) []Type {
 	return out
 }
 
-func WriteMockType(interfaceName string, typeData *TypeData) string {
-	packageDef := fmt.Sprintf("// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.\n\npackage %s\n\n", typeData.PackageName)
-
-	return packageDef + createImportsDef(typeData) + createStructDef(typeData, interfaceName) + createMethodsDef(typeData, interfaceName)
+// Options configures how the mock is written
+type Options struct {
+	// Template is the text/template source used to render the mock. If empty, DefaultTemplate is used.
+	// See DefaultTemplate for the data and helper functions available, and the blocks that can be overridden.
+	Template string
+	// Extensions contribute extra code to the mock, see Extension
+	Extensions []Extension
+	// Naming is the naming scheme for the mock type, its fields and receiver. Empty fields fall back to DefaultNaming.
+	Naming Naming
+	// Header is text, e.g. a license, added to the top of the mock file. It is turned into comments if it isn't already.
+	Header string
+	// BuildConstraint is a build constraint expression for the mock file, e.g. `testmocks`.
+	// It is combined with the build constraint of the file the interface is in.
+	BuildConstraint string
+	// LineDirectives adds `//line` directives mapping the mock's methods back to the interface methods they implement,
+	// so that panics and coverage point to the interface declaration. MockFilePath must be set.
+	LineDirectives bool
+	// MockFilePath is the path the mock file is written to
+	MockFilePath string
+	// OutPackage is the name of the package the mock is generated in, if it isn't the interface's package.
+	// Types from the interface's package are then qualified with its package name, and PackageImportPath must be set.
+	OutPackage string
+	// PackageImportPath is the import path of the interface's package, e.g. `github.com/jamesrr39/go-mockgen-tool/example`
+	PackageImportPath string
+	// Methods are the names of the methods to mock. The other methods are delegated to an implementation of the interface,
+	// which is embedded in the mock and passed to its constructor. If empty, all the methods are mocked.
+	Methods []string
+	// ExcludeMethods are the names of the methods not to mock, and delegate instead, see Methods. Only one of Methods and ExcludeMethods can be set.
+	ExcludeMethods []string
+	// OnUnset is what the mock's methods do when they are called without their fields set. If empty, they panic.
+	// Methods with `//mockgen:default-return` return their default values instead.
+	OnUnset OnUnset
+	// Testing adds a constructor taking the testing.TB of the test, e.g. `NewMockVehicle(t testing.TB)`. The methods of mocks created with it
+	// fail the test with t.Fatal when they are called without their fields set (unless OnUnset or `//mockgen:default-return` say otherwise),
+	// and the test fails when it ends if fields are set for methods that were never called.
+	// The mock file imports the testing package, so it is usually a _test.go file.
+	Testing bool
+	// Expectations adds typed expectations to the mock, e.g. `mock.ExpectGet(match.Eq("key")).Times(2).Return(item, nil)`, with the matchers
+	// of the match package. Calls that match none of the expectations of a method, and expected calls that aren't made, fail the test.
+	// Expectations need a mock created with the constructor of Testing, which is added, and Go 1.18 or later.
+	Expectations bool
+	// Returns adds methods setting the results of the calls to each method with results, e.g. `mock.WheelCountReturns(4, nil)` for all the calls,
+	// `mock.WheelCountReturnsOnCall(0, 0, errTimeout)` for the first call and `mock.WheelCountReturnsSequence(...)` for successive calls.
+	// They are used while the method's field isn't set. Calls after the end of a sequence panic, or fail the test for mocks created with
+	// the constructor of Testing.
+	Returns bool
+	// Order adds a RecordOrder method to the mock, recording its calls with a callorder.Recorder along with the calls to other mocks,
+	// and a method returning the callorder.Step of each method, e.g. `BeginCalled()`, to check the order of the calls across mocks with
+	// `recorder.InOrder(t, tx.BeginCalled(), store.PutCalled(), tx.CommitCalled())`.
+	Order bool
+	// Synchronized adds a setter for each field of the mock, e.g. `SetWheelCountFunc`, synchronized with the calls to the mock's methods,
+	// which read their fields behind the same mutex. The fields can then be set while the mock is used from other goroutines,
+	// without a data race.
+	Synchronized bool
+	// Spy generates a spy, e.g. `SpyVehicle`, created with `NewSpyVehicle(real Vehicle)`. Its methods delegate to real when their fields
+	// aren't set, and record the results of each call along with its arguments. The mock is named with DefaultSpyPrefix
+	// unless Naming says otherwise. Spies can't be partly mocked, see Methods.
+	Spy bool
+	// GoVersion is the Go version the mock is generated for, e.g. `1.18`, usually the go directive of the module's go.mod file (see GoVersionForDir).
+	// Empty interfaces are written as `any` from Go 1.18, `// +build` lines are left out from Go 1.17,
+	// and generic interfaces are refused before Go 1.18. If empty, the code is written as in the interface declaration.
+	GoVersion string
 }
 
-func createImportsDef(typeData *TypeData) string {
-	var importsDef string
-	if len(typeData.Imports) > 0 {
-		importsDef += "import (\n"
-		for _, im := range typeData.Imports {
-			importDef := "\t"
-			if im.Name != nil {
-				importDef += fmt.Sprintf("%s ", im.Name.Name)
-			}
-
-			importDef += im.Path.Value
-
-			importsDef += importDef + "\n"
-		}
-		importsDef += ")\n\n"
-	}
-	return importsDef
+// GeneratedMock is the generated code for a mock
+type GeneratedMock struct {
+	// Text is the content of the mock file
+	Text string
+	// ExtraFiles are the files contributed by extensions
+	ExtraFiles []File
 }
 
-func createStructDef(typeData *TypeData, interfaceName string) string {
-	structDef := fmt.Sprintf("type Mock%s struct {\n", interfaceName)
-	var longestMethodNameLen int
-	for _, method := range typeData.Methods {
-		if len(method.Name) > longestMethodNameLen {
-			longestMethodNameLen = len(method.Name)
-		}
-	}
-	methodMaxLengthForPadding := longestMethodNameLen + len(internalFuncSuffix)
-	// right-pad the method names to line them up
-	methodNameTemplate := fmt.Sprintf("\t%%-%ds func%%s\n", methodMaxLengthForPadding)
-	for _, method := range typeData.Methods {
-		methodName := method.Name + internalFuncSuffix
-		signature := fmt.Sprintf("(%s) %s", method.ParamsWithTypes(), method.ReturnTypesAsString())
-		structDef += fmt.Sprintf(methodNameTemplate, methodName, signature)
-	}
-	for _, embeddedInterface := range typeData.EmbeddedInterfaces {
-		structDef += fmt.Sprintf("\t%s\n", embeddedInterface)
+// WriteMockType renders the mock for the interface and formats it with gofmt.
+// Any extra files contributed by extensions are discarded, use GenerateMock to get them.
+func WriteMockType(interfaceName string, typeData *TypeData, options Options) (string, error) {
+	generatedMock, err := GenerateMock(interfaceName, typeData, options)
+	if err != nil {
+		return "", err
 	}
-	structDef += fmt.Sprintln("}")
-	return structDef
-}
 
-func createMethodsDef(typeData *TypeData, interfaceName string) string {
-	var methodsDef string
-	for _, method := range typeData.Methods {
-		hasReturn := len(method.ReturnTypes) != 0
+	return generatedMock.Text, nil
+}
 
-		returnKeywordText := ""
-		if hasReturn {
-			returnKeywordText = "return "
-		}
+// GenerateMock renders the mock for the interface, merging in the code contributed by extensions, and formats it with gofmt
+func GenerateMock(interfaceName string, typeData *TypeData, options Options) (*GeneratedMock, error) {
+	mockData, err := newMockData(interfaceName, typeData, options)
+	if err != nil {
+		return nil, err
+	}
 
-		var fullParamFragments []string
-		var paramNames []string
-		for i, param := range method.Params {
-			paramName := param.Name
-			if paramName == "" {
-				paramName = fmt.Sprintf("param%d", i)
-			}
-			paramNames = append(paramNames, paramName)
-			fullParamFragments = append(fullParamFragments, fmt.Sprintf("%s %s", paramName, param.FullTypeName()))
-		}
+	extraFiles, err := applyExtensions(mockData, options.Extensions)
+	if err != nil {
+		return nil, err
+	}
 
-		var returnFragments []string
-		for _, ret := range method.ReturnTypes {
-			returnFragments = append(returnFragments, ret.FullTypeName())
-		}
+	mockText, err := renderMockTemplate(mockData, options.Template)
+	if err != nil {
+		return nil, err
+	}
 
-		var retSignature string
-		switch len(returnFragments) {
-		case 0:
-			// do nothing, should be empty string
-		case 1:
-			retSignature = fmt.Sprintf("%s ", returnFragments[0])
-		default:
-			retSignature = fmt.Sprintf("(%s) ", strings.Join(returnFragments, ", "))
+	if options.LineDirectives {
+		mockText, err = addLineDirectives(mockText, mockData, options.MockFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("error adding line directives: %s", err)
 		}
-
-		methodsDef += fmt.Sprintf(`
-func (o *Mock%s) %s(%s) %s{
-	if o.%sFunc == nil {
-		panic("%sFunc not defined")
 	}
-	%so.%s%s(%s)
-}
-`, interfaceName, method.Name, strings.Join(fullParamFragments, ", "), retSignature,
-			method.Name,
-			method.Name,
-			returnKeywordText, method.Name, internalFuncSuffix, strings.Join(paramNames, ", "))
-	}
-	return methodsDef
+
+	return &GeneratedMock{
+		Text:       mockText,
+		ExtraFiles: extraFiles,
+	}, nil
 }
 
 func getNameForAstNode(sourceCode string, node ast.Node) string {
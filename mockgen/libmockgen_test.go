@@ -65,6 +65,12 @@ func Test_getTypesFromText(t *testing.T) {
 				{PackageName: "", TypeName: "int", Name: "d"},
 				{PackageName: "", TypeName: "func(a, b int) errors.Error", Name: "e"},
 			},
+		}, {
+			args: args{"format string, args ...extrapkg.Error"},
+			want: []Type{
+				{PackageName: "", TypeName: "string", Name: "format"},
+				{PackageName: "extrapkg", TypeName: "Error", Name: "args", Variadic: true},
+			},
 		}, {
 			args: args{"func(int, int, errors2.Error) errors.Error"},
 			want: []Type{
@@ -133,3 +139,98 @@ func Test_parseParams(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMethodsForType(t *testing.T) {
+	sourceCode := `package example
+
+import "fmt"
+
+// Logger logs things
+type Logger interface {
+	// Logf logs a formatted message
+	Logf(format string, args ...fmt.Stringer)
+	fmt.Stringer
+}
+`
+	typeData, err := GetMethodsForType(sourceCode, "Logger")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Logger logs things\n", typeData.Doc)
+	assert.Equal(t, 6, typeData.Position.Line)
+	assert.Equal(t, []string{"fmt.Stringer"}, typeData.EmbeddedInterfaces)
+	require.Len(t, typeData.Imports, 1)
+	assert.Equal(t, `"fmt"`, typeData.Imports[0].Path.Value)
+
+	require.Len(t, typeData.Methods, 1)
+	method := typeData.Methods[0]
+	assert.Equal(t, "Logf", method.Name)
+	assert.Equal(t, "Logf logs a formatted message\n", method.Doc)
+	assert.Equal(t, Position{Offset: 112, Line: 8, Column: 2}, method.Position)
+	assert.Equal(t, "format string, args ...fmt.Stringer", method.ParamsWithTypes())
+
+	_, err = GetMethodsForType(sourceCode, "Other")
+	assert.Equal(t, ErrInterfaceTypeNotFound, err)
+}
+
+func TestGetMethodsForType_funcType(t *testing.T) {
+	sourceCode := `package example
+
+import "context"
+
+// Handler handles requests
+type Handler func(ctx context.Context, req Request) (Response, error)
+`
+	typeData, err := GetMethodsForType(sourceCode, "Handler")
+	require.NoError(t, err)
+
+	assert.True(t, typeData.Func)
+	assert.Equal(t, "Handler handles requests\n", typeData.Doc)
+	require.Len(t, typeData.Imports, 1)
+	assert.Equal(t, `"context"`, typeData.Imports[0].Path.Value)
+	require.Len(t, typeData.Methods, 1)
+	assert.Equal(t, "Call", typeData.Methods[0].Name)
+	assert.Equal(t, "ctx context.Context, req Request", typeData.Methods[0].ParamsWithTypes())
+
+	mockText, err := WriteMockType("Handler", typeData, Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `// MockHandler is a mock implementation of [Handler].
+//
+// Handler handles requests
+type MockHandler struct {
+	// CallFunc is called by [MockHandler.Call].
+	CallFunc func(ctx context.Context, req Request) (Response, error)
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Call []MockHandlerCall
+	}
+}
+
+// compile time check that the mock's Call method is a Handler
+var _ Handler = (&MockHandler{}).Call
+
+// Handler returns the mock's Call method as a [Handler], to pass to the code under test.
+func (o *MockHandler) Handler() Handler {
+	return o.Call
+}
+
+// Call implements [Handler] by calling CallFunc.
+func (o *MockHandler) Call(ctx context.Context, req Request) (Response, error) {
+`)
+	assert.Contains(t, mockText, `
+// MockHandlerCall is a call to [MockHandler.Call], with its arguments.
+type MockHandlerCall struct {
+	Ctx context.Context
+	Req Request
+}
+
+// Calls returns the calls to [MockHandler.Call], in the order they were made.
+func (o *MockHandler) Calls() []MockHandlerCall {
+`)
+	assert.Contains(t, mockText, "func (o *MockHandler) CallCount() int {\n")
+
+	_, err = WriteMockType("Handler", typeData, Options{ExcludeMethods: []string{"Call"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// lineDirectiveInsert is a `//line` directive to be inserted into the mock source, at the start of a line
+type lineDirectiveInsert struct {
+	offset int
+	// target is the position the directive sets for the next line, e.g. `../vehicle.go:5`.
+	// If empty, the directive resets the position to the next line of the mock file itself.
+	target string
+	// separate is true for directives between declarations, which have a blank line before and after them, so that gofmt
+	// leaves them alone rather than adding them to a doc comment
+	separate bool
+}
+
+// addLineDirectives adds line directives to the formatted mock source, so that panics and coverage in the mock's methods
+// are attributed to the declaration of the interface method they implement.
+// Positions after each method are reset to the mock file itself, so that other code in the mock file keeps its own positions.
+// Only whole-line `//line` directives are added, in the places where gofmt leaves them as they are, so that the mock stays gofmt-formatted.
+func addLineDirectives(mockSource string, mockData *MockData, mockFilePath string) (string, error) {
+	if mockFilePath == "" {
+		return "", errors.New("the path of the mock file is needed for line directives")
+	}
+
+	mockDir, err := filepath.Abs(filepath.Dir(mockFilePath))
+	if err != nil {
+		return "", err
+	}
+
+	fileSet := token.NewFileSet()
+	parsedFile, err := parser.ParseFile(fileSet, mockFilePath, mockSource, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+	tokenFile := fileSet.File(parsedFile.Pos())
+
+	methodsByName := make(map[string]MockMethod)
+	for _, method := range mockData.Methods {
+		methodsByName[method.Name] = method
+	}
+
+	var inserts []lineDirectiveInsert
+	for _, decl := range parsedFile.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || !isMockMethod(funcDecl, mockData.MockName) {
+			continue
+		}
+
+		method, ok := methodsByName[funcDecl.Name.Name]
+		if !ok || method.Position.Filename == "" || method.Position.Line == 0 {
+			// no position to point back to
+			continue
+		}
+
+		sourceFilePath, err := filepath.Abs(method.Position.Filename)
+		if err != nil {
+			return "", err
+		}
+		sourceFilePath, err = filepath.Rel(mockDir, sourceFilePath)
+		if err != nil {
+			return "", err
+		}
+		sourceFilePath = filepath.ToSlash(sourceFilePath)
+
+		// the directive goes before the doc comment, separated from it, so the doc comment stays attached to the method.
+		// The blank line after the directive and the doc comment lines are mapped to the lines before the interface method.
+		startPos := funcDecl.Pos()
+		if funcDecl.Doc != nil {
+			startPos = funcDecl.Doc.Pos()
+		}
+		docLinesCount := tokenFile.Line(funcDecl.Pos()) - tokenFile.Line(startPos)
+		startLine := method.Position.Line - docLinesCount - 1
+		if startLine < 1 {
+			startLine = 1
+		}
+		inserts = append(inserts, lineDirectiveInsert{
+			offset:   tokenFile.Offset(tokenFile.LineStart(tokenFile.Line(startPos))),
+			target:   fmt.Sprintf("%s:%d", sourceFilePath, startLine),
+			separate: true,
+		})
+
+		// every line starting a statement in the method is attributed to the interface method
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			stmt, ok := node.(ast.Stmt)
+			if !ok {
+				return true
+			}
+			if _, isBlock := stmt.(*ast.BlockStmt); isBlock {
+				return true
+			}
+
+			offset := tokenFile.Offset(stmt.Pos())
+			lineStartOffset := tokenFile.Offset(tokenFile.LineStart(tokenFile.Line(stmt.Pos())))
+			if strings.TrimSpace(mockSource[lineStartOffset:offset]) != "" {
+				// only statements starting a line
+				return true
+			}
+
+			inserts = append(inserts, lineDirectiveInsert{
+				offset: lineStartOffset,
+				target: fmt.Sprintf("%s:%d", sourceFilePath, method.Position.Line),
+			})
+			return true
+		})
+
+		// reset the position to the mock file, after the method and the blank line following it
+		resetLine := tokenFile.Line(funcDecl.End()) + 1
+		if resetLine <= tokenFile.LineCount() && isBlankLine(mockSource, tokenFile, resetLine) {
+			resetLine++
+		}
+		if resetLine <= tokenFile.LineCount() {
+			inserts = append(inserts, lineDirectiveInsert{
+				offset:   tokenFile.Offset(tokenFile.LineStart(resetLine)),
+				separate: true,
+			})
+		}
+	}
+
+	sort.SliceStable(inserts, func(i, j int) bool {
+		return inserts[i].offset < inserts[j].offset
+	})
+
+	mockFileName := filepath.Base(mockFilePath)
+	var builder strings.Builder
+	var lastOffset int
+	for _, insert := range inserts {
+		builder.WriteString(mockSource[lastOffset:insert.offset])
+		lastOffset = insert.offset
+
+		if insert.separate && !strings.HasSuffix(builder.String(), "\n\n") {
+			builder.WriteString("\n")
+		}
+		target := insert.target
+		if target == "" {
+			// the line following the directive is the line of the mock file after the directive's own line
+			target = fmt.Sprintf("%s:%d", mockFileName, strings.Count(builder.String(), "\n")+2)
+		}
+		fmt.Fprintf(&builder, "//line %s\n", target)
+		if insert.separate {
+			builder.WriteString("\n")
+		}
+	}
+	builder.WriteString(mockSource[lastOffset:])
+
+	return builder.String(), nil
+}
+
+// isBlankLine returns true if the line of the source is empty
+func isBlankLine(source string, tokenFile *token.File, line int) bool {
+	start := tokenFile.Offset(tokenFile.LineStart(line))
+	end := len(source)
+	if line < tokenFile.LineCount() {
+		end = tokenFile.Offset(tokenFile.LineStart(line + 1))
+	}
+
+	return strings.TrimSpace(source[start:end]) == ""
+}
+
+// isMockMethod returns true if the function declaration is a method on the mock type
+func isMockMethod(funcDecl *ast.FuncDecl, mockName string) bool {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 {
+		return false
+	}
+
+	recvType := funcDecl.Recv.List[0].Type
+	if starExpr, ok := recvType.(*ast.StarExpr); ok {
+		recvType = starExpr.X
+	}
+	// generic mocks, e.g. `*MockCache[K, V]`
+	switch indexExpr := recvType.(type) {
+	case *ast.IndexExpr:
+		recvType = indexExpr.X
+	case *ast.IndexListExpr:
+		recvType = indexExpr.X
+	}
+	ident, ok := recvType.(*ast.Ident)
+
+	return ok && ident.Name == mockName
+}
@@ -0,0 +1,108 @@
+package mockgen
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMock_lineDirectives(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "go-mockgen-tool-line-directives")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	sourceCode := `package example
+
+type Vehicle interface {
+	// Name returns the name
+	Name() string
+	WheelCount() (int, error)
+}
+`
+	err = ioutil.WriteFile(filepath.Join(dirPath, "vehicle.go"), []byte(sourceCode), 0644)
+	require.NoError(t, err)
+
+	typeData, err := GetMethodsForTypeInDir(dirPath, "Vehicle")
+	require.NoError(t, err)
+
+	mockFilePath := filepath.Join(dirPath, "mocks", "vehicle_mock.go")
+	generatedMock, err := GenerateMock("Vehicle", typeData, Options{
+		LineDirectives: true,
+		MockFilePath:   mockFilePath,
+		Extensions:     []Extension{countingExtension{}},
+	})
+	require.NoError(t, err)
+
+	mockText := generatedMock.Text
+	assert.Contains(t, mockText, "//line ../vehicle.go:1\n\n// Name implements [Vehicle.Name] by calling NameFunc.")
+
+	// gofmt leaves the directives as they are
+	formatted, err := format.Source([]byte(mockText))
+	require.NoError(t, err)
+	assert.Equal(t, mockText, string(formatted))
+
+	fileSet := token.NewFileSet()
+	parsedFile, err := parser.ParseFile(fileSet, mockFilePath, mockText, parser.ParseComments)
+	require.NoError(t, err)
+	tokenFile := fileSet.File(parsedFile.Pos())
+
+	positionOf := func(text string) token.Position {
+		offset := strings.Index(mockText, text)
+		require.NotEqual(t, -1, offset, text)
+		return fileSet.Position(tokenFile.Pos(offset))
+	}
+
+	// the methods are attributed (relative paths in line directives are relative to the directory of the mock file) to the interface methods
+	namePanicPosition := positionOf(`panic("NameFunc not defined")`)
+	assert.Equal(t, filepath.Join(dirPath, "vehicle.go"), namePanicPosition.Filename)
+	assert.Equal(t, 5, namePanicPosition.Line)
+
+	wheelCountReturnPosition := positionOf(`return o.WheelCountFunc()`)
+	assert.Equal(t, filepath.Join(dirPath, "vehicle.go"), wheelCountReturnPosition.Filename)
+	assert.Equal(t, 6, wheelCountReturnPosition.Line)
+
+	// the doc comment stays attached to the method, without the directive
+	var nameDoc string
+	for _, decl := range parsedFile.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name.Name == "Name" {
+			nameDoc = funcDecl.Doc.Text()
+		}
+	}
+	assert.Equal(t, "Name implements [Vehicle.Name] by calling NameFunc.\n\nName returns the name\n", nameDoc)
+
+	// code after the methods keeps its position in the mock file
+	extensionDeclOffset := strings.Index(mockText, "func (o *MockVehicle) incrementCalls()")
+	extensionDeclPosition := fileSet.Position(tokenFile.Pos(extensionDeclOffset))
+	assert.Equal(t, "vehicle_mock.go", filepath.Base(extensionDeclPosition.Filename))
+	assert.Equal(t, strings.Count(mockText[:extensionDeclOffset], "\n")+1, extensionDeclPosition.Line)
+
+	_, err = GenerateMock("Vehicle", typeData, Options{LineDirectives: true})
+	require.Error(t, err)
+
+	t.Run("gofmt with all the options", func(t *testing.T) {
+		generatedMock, err := GenerateMock("Vehicle", typeData, Options{
+			LineDirectives: true,
+			MockFilePath:   mockFilePath,
+			Spy:            true,
+			Returns:        true,
+			Expectations:   true,
+			Synchronized:   true,
+			Order:          true,
+			GoVersion:      "1.18",
+		})
+		require.NoError(t, err)
+
+		formatted, err := format.Source([]byte(generatedMock.Text))
+		require.NoError(t, err)
+		assert.Equal(t, generatedMock.Text, string(formatted))
+	})
+}
@@ -0,0 +1,206 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ErrNoModule is returned when a directory is not in a Go module
+var ErrNoModule = errors.New("no go.mod file found")
+
+// modFile is the part of a go.mod or go.work file needed to generate mocks
+type modFile struct {
+	// Dir is the directory the go.mod file is in
+	Dir string
+	// ModulePath is the path of the module, from the `module` directive. It is empty for go.work files.
+	ModulePath string
+	// GoVersion is the Go version of the module, from the `go` directive
+	GoVersion string
+	// Requires are the versions of the required modules, by module path
+	Requires map[string]string
+	// Replaces are the `replace` directives
+	Replaces []modReplace
+	// Uses are the directories of the modules in a workspace, from the `use` directives of a go.work file
+	Uses []string
+}
+
+// modReplace is a `replace` directive, e.g. `example.com/b v1.0.0 => ../b`
+type modReplace struct {
+	OldPath, OldVersion string
+	// NewPath is either a module path or, if NewVersion is empty, a directory
+	NewPath, NewVersion string
+}
+
+// defaultModGoVersion is the Go version assumed for modules without a `go` directive
+const defaultModGoVersion = "1.16"
+
+// ImportPathForDir returns the import path of the package in the directory, based on the go.mod file of the module it is in
+func ImportPathForDir(dirPath string) (string, error) {
+	dirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return "", err
+	}
+
+	mod, err := findModFile(dirPath)
+	if err != nil {
+		return "", err
+	}
+
+	return mod.importPathForDir(dirPath)
+}
+
+// GoVersionForDir returns the Go version of the module the directory is in, from the go directive of its go.mod file
+func GoVersionForDir(dirPath string) (string, error) {
+	dirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return "", err
+	}
+
+	mod, err := findModFile(dirPath)
+	if err != nil {
+		return "", err
+	}
+
+	if mod.GoVersion == "" {
+		return defaultModGoVersion, nil
+	}
+
+	return mod.GoVersion, nil
+}
+
+// importPathForDir returns the import path of the package in the (absolute) directory, which must be in the module
+func (mod *modFile) importPathForDir(dirPath string) (string, error) {
+	relativePath, err := filepath.Rel(mod.Dir, dirPath)
+	if err != nil {
+		return "", err
+	}
+	if relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not in module %s", dirPath, mod.ModulePath)
+	}
+
+	return path.Join(mod.ModulePath, filepath.ToSlash(relativePath)), nil
+}
+
+// findModFile reads the go.mod file of the module the (absolute) directory is in
+func findModFile(dirPath string) (*modFile, error) {
+	modFilePath, err := findFileUpwards(dirPath, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+	if modFilePath == "" {
+		return nil, ErrNoModule
+	}
+
+	return readModFile(modFilePath)
+}
+
+// findFileUpwards looks for the file in the (absolute) directory and its parents. It returns an empty path if the file isn't found.
+func findFileUpwards(dirPath, fileName string) (string, error) {
+	for {
+		filePath := filepath.Join(dirPath, fileName)
+		_, err := os.Stat(filePath)
+		if err == nil {
+			return filePath, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parentDirPath := filepath.Dir(dirPath)
+		if parentDirPath == dirPath {
+			return "", nil
+		}
+		dirPath = parentDirPath
+	}
+}
+
+func readModFile(modFilePath string) (*modFile, error) {
+	mod, err := parseModFile(modFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if mod.ModulePath == "" {
+		return nil, fmt.Errorf("no module directive found in %s", modFilePath)
+	}
+
+	return mod, nil
+}
+
+// parseModFile parses a go.mod or go.work file
+func parseModFile(modFilePath string) (*modFile, error) {
+	data, err := ioutil.ReadFile(modFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	mod := &modFile{
+		Dir:      filepath.Dir(modFilePath),
+		Requires: make(map[string]string),
+	}
+
+	if filepath.Base(modFilePath) == "go.work" {
+		workFile, err := modfile.ParseWork(modFilePath, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		if workFile.Go != nil {
+			mod.GoVersion = workFile.Go.Version
+		}
+		for _, use := range workFile.Use {
+			mod.Uses = append(mod.Uses, mod.localPath(use.Path))
+		}
+		mod.addReplaces(workFile.Replace)
+
+		return mod, nil
+	}
+
+	file, err := modfile.Parse(modFilePath, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if file.Module != nil {
+		mod.ModulePath = file.Module.Mod.Path
+	}
+	if file.Go != nil {
+		mod.GoVersion = file.Go.Version
+	}
+	for _, require := range file.Require {
+		mod.Requires[require.Mod.Path] = require.Mod.Version
+	}
+	mod.addReplaces(file.Replace)
+
+	return mod, nil
+}
+
+// addReplaces adds the replace directives, with the directories they replace modules with relative to the file's directory
+func (mod *modFile) addReplaces(replaces []*modfile.Replace) {
+	for _, replace := range replaces {
+		newPath := replace.New.Path
+		if replace.New.Version == "" {
+			newPath = mod.localPath(newPath)
+		}
+		mod.Replaces = append(mod.Replaces, modReplace{
+			OldPath:    replace.Old.Path,
+			OldVersion: replace.Old.Version,
+			NewPath:    newPath,
+			NewVersion: replace.New.Version,
+		})
+	}
+}
+
+// localPath returns the directory of a path in the file, e.g. `../b`, which is relative to the file's directory unless it is absolute
+func (mod *modFile) localPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(mod.Dir, filepath.FromSlash(path))
+}
@@ -0,0 +1,82 @@
+package mockgen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPathForDir(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "mockgen-modules-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(moduleDir)
+
+	err = ioutil.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte("// the example module\nmodule \"example.com/vehicles\" // quoted\n\ngo 1.16\n"), 0644)
+	require.NoError(t, err)
+
+	packageDir := filepath.Join(moduleDir, "cars", "electric")
+	err = os.MkdirAll(packageDir, 0755)
+	require.NoError(t, err)
+
+	importPath, err := ImportPathForDir(packageDir)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/vehicles/cars/electric", importPath)
+
+	importPath, err = ImportPathForDir(moduleDir)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/vehicles", importPath)
+
+	goVersion, err := GoVersionForDir(packageDir)
+	require.NoError(t, err)
+	assert.Equal(t, "1.16", goVersion)
+}
+
+func Test_parseModFile(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-modules-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	writeTestFiles(t, dirPath, map[string]string{
+		"go.mod": `module "example.com/vehicles" // quoted
+
+go 1.21
+
+toolchain go1.21.3
+
+require (
+	example.com/wheels v1.2.0 // indirect
+	"example.com/engines" v0.3.0
+)
+
+replace (
+	example.com/wheels v1.2.0 => ../wheels
+	example.com/engines => example.com/fork/engines v0.4.0
+)
+
+retract [v0.1.0, v0.1.5] // published by mistake
+`,
+		"go.work": "go 1.21\n\nuse (\n\t.\n\t../wheels\n)\n\nreplace example.com/engines => ./engines\n",
+	})
+
+	mod, err := parseModFile(filepath.Join(dirPath, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/vehicles", mod.ModulePath)
+	assert.Equal(t, "1.21", mod.GoVersion)
+	assert.Equal(t, map[string]string{"example.com/wheels": "v1.2.0", "example.com/engines": "v0.3.0"}, mod.Requires)
+	assert.Equal(t, []modReplace{
+		{OldPath: "example.com/wheels", OldVersion: "v1.2.0", NewPath: filepath.Join(filepath.Dir(dirPath), "wheels")},
+		{OldPath: "example.com/engines", NewPath: "example.com/fork/engines", NewVersion: "v0.4.0"},
+	}, mod.Replaces)
+
+	workFile, err := parseModFile(filepath.Join(dirPath, "go.work"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{dirPath, filepath.Join(filepath.Dir(dirPath), "wheels")}, workFile.Uses)
+	assert.Equal(t, []modReplace{{OldPath: "example.com/engines", NewPath: filepath.Join(dirPath, "engines")}}, workFile.Replaces)
+
+	_, err = parseModFile(filepath.Join(dirPath, "missing", "go.mod"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/token"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Naming configures the names used in the generated code
+type Naming struct {
+	// MockPrefix and MockSuffix are added to the interface name to make the name of the mock type.
+	// If both are empty, "Mock" is used as the prefix.
+	// The mock type is exported if the interface is, e.g. `Vehicle` becomes `MockVehicle` and `vehicle` becomes `mockVehicle`.
+	MockPrefix, MockSuffix string
+	// MockName is the name of the mock type. If set, MockPrefix and MockSuffix are ignored.
+	MockName string
+	// FieldSuffix is added to method names to make the names of the fields that set the methods' behaviour. Defaults to "Func".
+	FieldSuffix string
+	// Receiver is the name of the receiver in the mock's methods. Defaults to "o".
+	Receiver string
+}
+
+// DefaultSpyPrefix is the prefix of the names of spies when neither a prefix nor a suffix is given, e.g. `SpyVehicle`, see Options.Spy
+const DefaultSpyPrefix = "Spy"
+
+// DefaultNaming is the naming scheme used when none is given
+var DefaultNaming = Naming{
+	MockPrefix:  "Mock",
+	FieldSuffix: internalFuncSuffix,
+	Receiver:    "o",
+}
+
+func (naming Naming) mockName(interfaceName string) string {
+	if naming.MockName != "" {
+		return naming.MockName
+	}
+
+	prefix, suffix := naming.MockPrefix, naming.MockSuffix
+	if prefix == "" && suffix == "" {
+		prefix = DefaultNaming.MockPrefix
+	}
+
+	var mockName string
+	if prefix == "" {
+		mockName = interfaceName + suffix
+	} else {
+		mockName = prefix + upperFirst(interfaceName) + suffix
+	}
+
+	if token.IsExported(interfaceName) {
+		return upperFirst(mockName)
+	}
+
+	return lowerFirst(mockName)
+}
+
+func (naming Naming) fieldName(methodName string) string {
+	fieldSuffix := naming.FieldSuffix
+	if fieldSuffix == "" {
+		fieldSuffix = DefaultNaming.FieldSuffix
+	}
+
+	return methodName + fieldSuffix
+}
+
+func (naming Naming) receiver() string {
+	if naming.Receiver == "" {
+		return DefaultNaming.Receiver
+	}
+
+	return naming.Receiver
+}
+
+// receiverForMethods returns the receiver name for the mock's methods, which must not be the name of one of their parameters.
+// The default receiver gets underscores added until it isn't, e.g. `o_`, but a receiver set in the naming is an error.
+func (naming Naming) receiverForMethods(methods []MockMethod) (string, error) {
+	paramNames := make(map[string]string)
+	for _, method := range methods {
+		for _, paramName := range method.ParamNames() {
+			paramNames[paramName] = method.Name
+		}
+	}
+
+	receiver := naming.receiver()
+	for {
+		methodName, ok := paramNames[receiver]
+		if !ok {
+			return receiver, nil
+		}
+		if naming.Receiver != "" {
+			return "", fmt.Errorf("the receiver %s has the same name as a parameter of %s. Set another receiver name with --receiver", receiver, methodName)
+		}
+		receiver += "_"
+	}
+}
+
+func upperFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// constructorName returns the name of a constructor function for the mock, e.g. `NewMockVehicle`, or `newMockVehicle` for unexported mocks
+func constructorName(mockName string) string {
+	if token.IsExported(mockName) {
+		return "New" + mockName
+	}
+
+	return "new" + upperFirst(mockName)
+}
@@ -0,0 +1,85 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNaming_mockName(t *testing.T) {
+	tests := []struct {
+		name          string
+		naming        Naming
+		interfaceName string
+		want          string
+	}{
+		{"default, exported", DefaultNaming, "Vehicle", "MockVehicle"},
+		{"default, unexported", DefaultNaming, "vehicle", "mockVehicle"},
+		{"empty naming", Naming{}, "Vehicle", "MockVehicle"},
+		{"suffix only", Naming{MockSuffix: "Mock"}, "Vehicle", "VehicleMock"},
+		{"suffix only, unexported", Naming{MockSuffix: "Mock"}, "vehicle", "vehicleMock"},
+		{"suffix without the default prefix", Naming{MockSuffix: "Fake"}, "Health", "HealthFake"},
+		{"prefix and suffix", Naming{MockPrefix: "fake", MockSuffix: "Impl"}, "Vehicle", "FakeVehicleImpl"},
+		{"explicit name", Naming{MockPrefix: "Mock", MockName: "stubVehicle"}, "Vehicle", "stubVehicle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.naming.mockName(tt.interfaceName))
+		})
+	}
+}
+
+func TestWriteMockType_naming(t *testing.T) {
+	sourceCode := `package example
+
+type vehicle interface {
+	Name() string
+}
+`
+	typeData, err := GetMethodsForType(sourceCode, "vehicle")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("vehicle", typeData, Options{Naming: Naming{FieldSuffix: "Stub", Receiver: "m"}})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `type mockVehicle struct {
+	// NameStub is called by [mockVehicle.Name].
+	NameStub func() string
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Name []mockVehicleNameCall
+	}
+}
+
+var (
+	// compile time checks that *mockVehicle implements the interface and the interfaces it embeds
+	_ vehicle = &mockVehicle{}
+)
+
+// Name implements [vehicle.Name] by calling NameStub.
+func (m *mockVehicle) Name() string {
+	m.mockMu.Lock()
+	m.mockCalls.Name = append(m.mockCalls.Name, mockVehicleNameCall{})
+	m.mockMu.Unlock()
+
+	if m.NameStub == nil {
+		panic("NameStub not defined")
+	}
+	return m.NameStub()
+}`)
+}
+
+func TestWriteMockType_receiverNamedLikeParameter(t *testing.T) {
+	typeData, err := GetMethodsForType("package example\ntype Writer interface {\n\tWrite(o []byte) (int, error)\n}\n", "Writer")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Writer", typeData, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, mockText, "func (o_ *MockWriter) Write(o []byte) (int, error) {")
+
+	_, err = WriteMockType("Writer", typeData, Options{Naming: Naming{Receiver: "o"}})
+	assert.EqualError(t, err, "the receiver o has the same name as a parameter of Write. Set another receiver name with --receiver")
+}
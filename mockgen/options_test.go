@@ -0,0 +1,103 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMockType_optionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		typeName string
+		options  Options
+		wantErr  string
+	}{
+		{
+			name:     "unknown behaviour for unset fields",
+			source:   "package example\ntype Store interface {\n\tGet(key string) (string, error)\n}\n",
+			typeName: "Store",
+			options:  Options{OnUnset: "ignore"},
+			wantErr:  `unknown behaviour for unset fields "ignore", expected panic, zero or error`,
+		}, {
+			name:     "call accessor named like a method",
+			source:   "package example\ntype Counter interface {\n\tAdd(n int)\n\tAddCallCount() int\n}\n",
+			typeName: "Counter",
+			wantErr:  "the mock's AddCallCount method, returning the number of calls to Add, has the same name as a method of the interface",
+		}, {
+			name:     "function type named Call",
+			source:   "package example\ntype Call func(n int) error\n",
+			typeName: "Call",
+			wantErr:  "the mock's Call method, returning the mock as a Call, has the same name as the method called with the function's arguments",
+		}, {
+			name:     "function type named Calls",
+			source:   "package example\ntype Calls func()\n",
+			typeName: "Calls",
+			wantErr:  "the mock's Calls method, returning the mock as a Calls, has the same name as the method returning the recorded calls",
+		}, {
+			name:     "function type named CallCount",
+			source:   "package example\ntype CallCount func() int\n",
+			typeName: "CallCount",
+			wantErr:  "the mock's CallCount method, returning the mock as a CallCount, has the same name as the method returning the number of calls",
+		}, {
+			name:     "expectations for a Go version without generics",
+			source:   "package example\ntype Store interface {\n\tGet(key string) (string, error)\n}\n",
+			typeName: "Store",
+			options:  Options{Expectations: true, GoVersion: "1.17"},
+			wantErr:  "expectations use generics, which need Go 1.18 or later, but the mock is generated for Go 1.17. Set the Go version with the go directive in go.mod or --go-version",
+		}, {
+			name:     "expectations with a package named match",
+			source:   "package example\nimport \"example.com/match\"\ntype Matcher interface {\n\tMatch(r match.Rule) bool\n}\n",
+			typeName: "Matcher",
+			options:  Options{Expectations: true},
+			wantErr:  "the mock uses package example.com/match, which has the same name as the package of the matchers used by expectations, github.com/jamesrr39/go-mockgen-tool/match",
+		}, {
+			name:     "results method named like a method",
+			source:   "package example\ntype Vehicle interface {\n\tWheelCount() int\n\tWheelCountReturnsOnCall()\n}\n",
+			typeName: "Vehicle",
+			options:  Options{Returns: true},
+			wantErr:  "the mock's WheelCountReturnsOnCall method, setting the results of WheelCount, has the same name as a method of the interface",
+		}, {
+			name:     "order method named like a method",
+			source:   "package example\ntype Recorder interface {\n\tRecordOrder()\n}\n",
+			typeName: "Recorder",
+			options:  Options{Order: true},
+			wantErr:  "the mock's RecordOrder method, recording the order of the calls, has the same name as a method of the interface",
+		}, {
+			name:     "order with a package named callorder",
+			source:   "package example\nimport \"example.com/callorder\"\ntype Queue interface {\n\tPush(s callorder.Step)\n}\n",
+			typeName: "Queue",
+			options:  Options{Order: true},
+			wantErr:  "the mock uses package example.com/callorder, which has the same name as the package recording the order of the calls, github.com/jamesrr39/go-mockgen-tool/callorder",
+		}, {
+			name:     "setter named like a method",
+			source:   "package example\ntype Counter interface {\n\tAdd(n int)\n\tSetAddFunc()\n}\n",
+			typeName: "Counter",
+			options:  Options{Synchronized: true},
+			wantErr:  "the mock's SetAddFunc method, setting AddFunc, has the same name as a method of the interface",
+		}, {
+			name:     "spy with a parameter named results",
+			source:   "package example\ntype Checker interface {\n\tCheck(results []string) error\n}\n",
+			typeName: "Checker",
+			options:  Options{Spy: true},
+			wantErr:  "the parameter results of Check has the same name as the results recorded in SpyCheckerCheckCall",
+		}, {
+			name:     "partly mocked spy",
+			source:   "package example\ntype Vehicle interface {\n\tWheelCount() (int, error)\n\tHonk()\n}\n",
+			typeName: "Vehicle",
+			options:  Options{Spy: true, Methods: []string{"WheelCount"}},
+			wantErr:  "spies delegate all the methods without fields to the real implementation, so they can't be partly mocked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeData, err := GetMethodsForType(tt.source, tt.typeName)
+			require.NoError(t, err)
+
+			_, err = WriteMockType(tt.typeName, typeData, tt.options)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package mockgen
+
+import (
+	"fmt"
+	"path"
+)
+
+// callOrderPackagePath is the import path of the package recording the order of the calls across mocks, see Options.Order
+const callOrderPackagePath = "github.com/jamesrr39/go-mockgen-tool/callorder"
+
+// recordOrderMethodName is the name of the mock's method registering it with a callorder.Recorder
+const recordOrderMethodName = "RecordOrder"
+
+// RecordsOrder is true if the mock can record the order of the calls to its methods with a callorder.Recorder, see Options.Order
+func (mockData *MockData) RecordsOrder() bool {
+	return mockData.Order && mockData.RecordsCalls()
+}
+
+// CalledMethodName is the name of the mock's method returning the callorder.Step of a call to the method, e.g. `BeginCalled`,
+// or `Called` for mocks of function types
+func (method MockMethod) CalledMethodName() string {
+	if method.Mock.Func {
+		return "Called"
+	}
+
+	return method.Name + "Called"
+}
+
+// checkOrder checks that the methods added to the mock for recording the order of the calls don't have the same names as the mocked methods,
+// and that the mock can import the callorder package
+func checkOrder(mockData *MockData) error {
+	methodNames := make(map[string]struct{})
+	for _, method := range mockData.Methods {
+		methodNames[method.Name] = struct{}{}
+	}
+
+	if _, ok := methodNames[recordOrderMethodName]; ok {
+		return fmt.Errorf("the mock's %s method, recording the order of the calls, has the same name as a method of the interface", recordOrderMethodName)
+	}
+	for _, method := range mockData.Methods {
+		if _, ok := methodNames[method.CalledMethodName()]; ok && method.Recorded() {
+			return fmt.Errorf("the mock's %s method, returning the step of a call to %s, has the same name as a method of the interface", method.CalledMethodName(), method.Name)
+		}
+	}
+
+	for _, im := range mockData.Imports {
+		name := im.Name
+		if name == "" {
+			name = path.Base(im.Path)
+		}
+		if name == "callorder" && im.Path != callOrderPackagePath {
+			return fmt.Errorf("the mock uses package %s, which has the same name as the package recording the order of the calls, %s", im.Path, callOrderPackagePath)
+		}
+	}
+
+	return nil
+}
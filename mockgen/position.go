@@ -0,0 +1,187 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+var ErrNoInterfaceAtPosition = errors.New("no interface type found at position")
+
+// Position is a location in a Go source file, as passed in by an editor.
+type Position struct {
+	Filename string
+	// Offset is the byte offset into the file. It is only used when Line is 0.
+	Offset int
+	// Line and Column are 1-based. Column is counted in bytes.
+	Line, Column int
+}
+
+// ParsePosition parses a position in the form `path/to/file.go:#1234` (byte offset) or `path/to/file.go:12:5` (line and column).
+func ParsePosition(pos string) (Position, error) {
+	hashIdx := strings.LastIndex(pos, ":#")
+	if hashIdx != -1 {
+		offset, err := strconv.Atoi(pos[hashIdx+2:])
+		if err != nil || offset < 0 {
+			return Position{}, fmt.Errorf("invalid byte offset in position %q", pos)
+		}
+		return Position{Filename: pos[:hashIdx], Offset: offset}, nil
+	}
+
+	fragments := strings.Split(pos, ":")
+	if len(fragments) < 3 {
+		return Position{}, fmt.Errorf("invalid position %q. Expected <file>:#<offset> or <file>:<line>:<column>", pos)
+	}
+
+	line, err := strconv.Atoi(fragments[len(fragments)-2])
+	if err != nil || line < 1 {
+		return Position{}, fmt.Errorf("invalid line in position %q", pos)
+	}
+	column, err := strconv.Atoi(fragments[len(fragments)-1])
+	if err != nil || column < 1 {
+		return Position{}, fmt.Errorf("invalid column in position %q", pos)
+	}
+
+	return Position{
+		Filename: strings.Join(fragments[:len(fragments)-2], ":"),
+		Line:     line,
+		Column:   column,
+	}, nil
+}
+
+func (p Position) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s:#%d", p.Filename, p.Offset)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// offsetIn returns the byte offset of the position in the source code
+func (p Position) offsetIn(sourceCode string) (int, error) {
+	if p.Line == 0 {
+		if p.Offset > len(sourceCode) {
+			return 0, fmt.Errorf("offset %d is past the end of the file (%d bytes)", p.Offset, len(sourceCode))
+		}
+		return p.Offset, nil
+	}
+
+	offset := 0
+	for line := 1; line < p.Line; line++ {
+		newlineIdx := strings.IndexByte(sourceCode[offset:], '\n')
+		if newlineIdx == -1 {
+			return 0, fmt.Errorf("line %d is past the end of the file", p.Line)
+		}
+		offset += newlineIdx + 1
+	}
+
+	offset += p.Column - 1
+	if offset > len(sourceCode) {
+		return 0, fmt.Errorf("column %d is past the end of the file", p.Column)
+	}
+
+	return offset, nil
+}
+
+// GetMethodsForPosition finds the interface type at the position and returns it, along with the interface's name.
+// The position can be anywhere inside an interface type (named or anonymous), on the name of an interface declaration or on a reference to an interface declared in the same file.
+// Anonymous interfaces, e.g. `func Do(reader interface{ Read() error })`, are named after the field or variable they are the type of ("Reader").
+func GetMethodsForPosition(sourceCode string, position Position) (*TypeData, string, error) {
+	fileSet := token.NewFileSet()
+	parsedFile, err := parser.ParseFile(fileSet, position.Filename, sourceCode, parser.ParseComments)
+	if err != nil {
+		return nil, "", err
+	}
+
+	offset, err := position.offsetIn(sourceCode)
+	if err != nil {
+		return nil, "", err
+	}
+	pos := fileSet.File(parsedFile.Pos()).Pos(offset)
+
+	// find the path of nodes from the file down to the innermost node enclosing the position
+	var currentPath, enclosingPath []ast.Node
+	ast.Inspect(parsedFile, func(node ast.Node) bool {
+		if node == nil {
+			currentPath = currentPath[:len(currentPath)-1]
+			return true
+		}
+		if pos < node.Pos() || pos >= node.End() {
+			return false
+		}
+		currentPath = append(currentPath, node)
+		enclosingPath = append([]ast.Node(nil), currentPath...)
+		return true
+	})
+
+	interfaceType, interfaceName := findInterfaceInPath(enclosingPath)
+	if interfaceType == nil {
+		return nil, "", ErrNoInterfaceAtPosition
+	}
+
+	typeData, err := getTypeDataForInterface(fileSet, sourceCode, parsedFile, interfaceType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return typeData, interfaceName, nil
+}
+
+// findInterfaceInPath finds the innermost interface in a path of nodes, going from the outermost to the innermost node.
+// It returns nil if there is no interface in the path.
+func findInterfaceInPath(enclosingPath []ast.Node) (*ast.InterfaceType, string) {
+	for i := len(enclosingPath) - 1; i >= 0; i-- {
+		switch n := enclosingPath[i].(type) {
+		case *ast.Ident:
+			// reference to an interface declared in this file
+			if n.Obj == nil {
+				continue
+			}
+			typeSpec, ok := n.Obj.Decl.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			return interfaceType, typeSpec.Name.Name
+		case *ast.TypeSpec:
+			interfaceType, ok := n.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			return interfaceType, n.Name.Name
+		case *ast.InterfaceType:
+			var parent ast.Node
+			if i > 0 {
+				parent = enclosingPath[i-1]
+			}
+			return n, nameForInterfaceLiteral(parent)
+		}
+	}
+
+	return nil, ""
+}
+
+// nameForInterfaceLiteral picks a name for an interface type, based on the node it is found in
+func nameForInterfaceLiteral(parent ast.Node) string {
+	var names []*ast.Ident
+	switch p := parent.(type) {
+	case *ast.TypeSpec:
+		return p.Name.Name
+	case *ast.Field:
+		names = p.Names
+	case *ast.ValueSpec:
+		names = p.Names
+	}
+
+	if len(names) == 0 || names[0].Name == "_" {
+		return "Anonymous"
+	}
+
+	return upperFirst(names[0].Name)
+}
@@ -0,0 +1,149 @@
+package mockgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		pos     string
+		want    Position
+		wantErr bool
+	}{
+		{
+			name: "byte offset",
+			pos:  "path/to/file.go:#1234",
+			want: Position{Filename: "path/to/file.go", Offset: 1234},
+		}, {
+			name: "line and column",
+			pos:  "path/to/file.go:12:5",
+			want: Position{Filename: "path/to/file.go", Line: 12, Column: 5},
+		}, {
+			name: "windows path with line and column",
+			pos:  `C:\path\file.go:12:5`,
+			want: Position{Filename: `C:\path\file.go`, Line: 12, Column: 5},
+		}, {
+			name:    "no position",
+			pos:     "path/to/file.go",
+			wantErr: true,
+		}, {
+			name:    "bad offset",
+			pos:     "path/to/file.go:#abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePosition(tt.pos)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+const positionTestSource = `package example
+
+import "io"
+
+type Vehicle interface {
+	Name() string
+}
+
+func Process(v Vehicle, reader interface {
+	Read(p []byte) (int, error)
+	io.Closer
+}) {
+}
+`
+
+func TestGetMethodsForPosition(t *testing.T) {
+	offsetOf := func(text string) Position {
+		return Position{Filename: "example.go", Offset: strings.Index(positionTestSource, text)}
+	}
+
+	tests := []struct {
+		name              string
+		pos               Position
+		wantInterfaceName string
+		wantMethods       []string
+		wantEmbedded      []string
+		wantLiteral       string
+		wantErr           error
+	}{
+		{
+			name:              "inside named interface",
+			pos:               offsetOf("Name() string"),
+			wantInterfaceName: "Vehicle",
+			wantMethods:       []string{"Name"},
+		}, {
+			name:              "on name of declaration",
+			pos:               Position{Filename: "example.go", Line: 5, Column: 7},
+			wantInterfaceName: "Vehicle",
+			wantMethods:       []string{"Name"},
+		}, {
+			name:              "on reference to interface",
+			pos:               offsetOf("Vehicle, reader"),
+			wantInterfaceName: "Vehicle",
+			wantMethods:       []string{"Name"},
+		}, {
+			name:              "anonymous interface parameter",
+			pos:               offsetOf("Read(p"),
+			wantInterfaceName: "Reader",
+			wantMethods:       []string{"Read"},
+			wantEmbedded:      []string{"io.Closer"},
+			wantLiteral:       "interface {\n\tRead(p []byte) (int, error)\n\tio.Closer\n}",
+		}, {
+			name:    "outside of an interface",
+			pos:     offsetOf("package"),
+			wantErr: ErrNoInterfaceAtPosition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeData, interfaceName, err := GetMethodsForPosition(positionTestSource, tt.pos)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantInterfaceName, interfaceName)
+			var methodNames []string
+			for _, method := range typeData.Methods {
+				methodNames = append(methodNames, method.Name)
+			}
+			assert.Equal(t, tt.wantMethods, methodNames)
+			assert.Equal(t, tt.wantEmbedded, typeData.EmbeddedInterfaces)
+			assert.Equal(t, tt.wantLiteral, typeData.Literal)
+		})
+	}
+}
+
+func TestWriteMockType_anonymousInterfaceAssertion(t *testing.T) {
+	typeData, interfaceName, err := GetMethodsForPosition(positionTestSource, Position{Filename: "example.go", Offset: strings.Index(positionTestSource, "Read(p")})
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType(interfaceName, typeData, Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `	_ interface {
+		Read(p []byte) (int, error)
+		io.Closer
+	} = &MockReader{}
+	_ io.Closer = &MockReader{}
+`)
+
+	// there is no declared type to link to
+	assert.Contains(t, mockText, "// MockReader is a mock implementation of an anonymous interface.\n")
+	assert.Contains(t, mockText, "// Read implements Read of the anonymous interface by calling ReadFunc.\n")
+	assert.NotContains(t, mockText, "[Reader")
+}
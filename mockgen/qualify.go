@@ -0,0 +1,247 @@
+package mockgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+var identifierRegex = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_]*$`)
+
+// predeclaredIdents are the predeclared types, constants and functions, which are never qualified with a package name
+var predeclaredIdents = toSet(
+	"any", "bool", "byte", "comparable", "complex64", "complex128", "error", "float32", "float64",
+	"int", "int8", "int16", "int32", "int64", "rune", "string",
+	"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+	"true", "false", "iota", "nil",
+	"append", "cap", "clear", "close", "complex", "copy", "delete", "imag", "len", "make",
+	"max", "min", "new", "panic", "print", "println", "real", "recover",
+)
+
+// qualifyTypeData returns a copy of the type data for a mock in another package (outPackage),
+// with the types declared in the interface's package qualified with its package name.
+// An error is returned if the interface can't be implemented outside its package, e.g. because it has unexported methods.
+func qualifyTypeData(typeData *TypeData, outPackage string) (*TypeData, error) {
+	if typeData.PackageName == "main" {
+		return nil, errors.New("interfaces in package main can't be mocked in another package, as package main can't be imported")
+	}
+
+	outTypeData, err := qualifyTypeDataWith(typeData, typeData.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	outTypeData.PackageName = outPackage
+
+	return outTypeData, nil
+}
+
+// qualifyTypeDataWith returns a copy of the type data with the types declared in the interface's package qualified with packageName,
+// the name the package is imported with. The package name of the copy is left as it is.
+func qualifyTypeDataWith(typeData *TypeData, packageName string) (*TypeData, error) {
+	for _, method := range typeData.Methods {
+		if !ast.IsExported(method.Name) {
+			return nil, fmt.Errorf("method %s is unexported, so it can't be implemented outside package %s", method.Name, typeData.PackageName)
+		}
+	}
+
+	typeParamNames := typeData.typeParamNames()
+	outTypeData, err := rewriteTypeData(typeData, func(typeExpr string) (string, error) {
+		return qualifyTypeExpr(typeExpr, packageName, typeParamNames)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, method := range outTypeData.Methods {
+		var defaultReturn []string
+		for _, value := range method.Annotations.DefaultReturn {
+			qualifiedValue, err := qualifyTypeExpr(value, packageName, typeParamNames)
+			if err != nil {
+				return nil, fmt.Errorf("method %s: %s", method.Name, err)
+			}
+			defaultReturn = append(defaultReturn, qualifiedValue)
+		}
+		outTypeData.Methods[i].Annotations.DefaultReturn = defaultReturn
+	}
+
+	return outTypeData, nil
+}
+
+// rewriteTypeData returns a copy of the type data, with all the type expressions in it (method parameters and results,
+// the interfaces they are from, embedded, flattened and combined interfaces, the interface literal and type parameter constraints) replaced by the result of rewrite
+func rewriteTypeData(typeData *TypeData, rewrite func(typeExpr string) (string, error)) (*TypeData, error) {
+	outTypeData := *typeData
+	outTypeData.Methods = nil
+	outTypeData.EmbeddedInterfaces = nil
+	outTypeData.FlattenedInterfaces = nil
+	outTypeData.Interfaces = nil
+	outTypeData.TypeParams = nil
+
+	var err error
+	for _, method := range typeData.Methods {
+		rewrittenMethod := method
+		rewrittenMethod.Params, err = rewriteTypes(method.Params, rewrite)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %s", method.Name, err)
+		}
+		rewrittenMethod.ReturnTypes, err = rewriteTypes(method.ReturnTypes, rewrite)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %s", method.Name, err)
+		}
+		if method.Interface != "" {
+			rewrittenMethod.Interface, err = rewrite(method.Interface)
+			if err != nil {
+				return nil, fmt.Errorf("method %s: %s", method.Name, err)
+			}
+		}
+		outTypeData.Methods = append(outTypeData.Methods, rewrittenMethod)
+	}
+
+	for _, embeddedInterface := range typeData.EmbeddedInterfaces {
+		rewrittenInterface, err := rewrite(embeddedInterface)
+		if err != nil {
+			return nil, err
+		}
+		outTypeData.EmbeddedInterfaces = append(outTypeData.EmbeddedInterfaces, rewrittenInterface)
+	}
+
+	for _, flattenedInterface := range typeData.FlattenedInterfaces {
+		rewrittenInterface, err := rewrite(flattenedInterface)
+		if err != nil {
+			return nil, err
+		}
+		outTypeData.FlattenedInterfaces = append(outTypeData.FlattenedInterfaces, rewrittenInterface)
+	}
+
+	for _, interfaceName := range typeData.Interfaces {
+		rewrittenInterface, err := rewrite(interfaceName)
+		if err != nil {
+			return nil, err
+		}
+		outTypeData.Interfaces = append(outTypeData.Interfaces, rewrittenInterface)
+	}
+
+	if typeData.Literal != "" {
+		outTypeData.Literal, err = rewrite(typeData.Literal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	outTypeData.TypeParams, err = rewriteTypes(typeData.TypeParams, rewrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outTypeData, nil
+}
+
+// rewriteTypes returns a copy of the types, with their full type names replaced by the result of rewrite
+func rewriteTypes(types []Type, rewrite func(typeExpr string) (string, error)) ([]Type, error) {
+	var rewrittenTypes []Type
+	for _, t := range types {
+		rewrittenTypeName, err := rewrite(t.FullTypeName())
+		if err != nil {
+			return nil, err
+		}
+
+		rewrittenType := fullTypeToType(rewrittenTypeName)
+		rewrittenType.Name = t.Name
+		rewrittenType.Variadic = t.Variadic
+		rewrittenTypes = append(rewrittenTypes, rewrittenType)
+	}
+
+	return rewrittenTypes, nil
+}
+
+// qualifyTypeExpr qualifies the identifiers declared in the interface's package in a type expression
+// (or the expression of a value, e.g. a default-return value) with the package name,
+// e.g. `func(mode DriveMode) []*Car` becomes `func(mode example.DriveMode) []*example.Car`.
+// Identifiers in localNames (type parameters) are left as they are.
+// An error is returned if the expression refers to unexported identifiers in the package.
+func qualifyTypeExpr(typeExpr, packageName string, localNames map[string]struct{}) (string, error) {
+	expr, err := parser.ParseExpr(typeExpr)
+	if err != nil {
+		return "", fmt.Errorf("couldn't parse type %q: %s", typeExpr, err)
+	}
+
+	var unexportedNames []string
+	visitTypeIdents(expr, func(ident *ast.Ident) {
+		if _, ok := predeclaredIdents[ident.Name]; ok {
+			return
+		}
+		if _, ok := localNames[ident.Name]; ok {
+			return
+		}
+		if !ast.IsExported(ident.Name) {
+			unexportedNames = append(unexportedNames, ident.Name)
+		}
+		ident.Name = packageName + "." + ident.Name
+	})
+
+	if len(unexportedNames) != 0 {
+		return "", fmt.Errorf("%s is unexported, so it can't be used outside package %s", strings.Join(unexportedNames, ", "), packageName)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = printer.Fprint(buf, token.NewFileSet(), expr)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// packagesInTypeExpr returns the package names referenced in a type expression, e.g. `extrapkg` for `func() []*extrapkg.Error`
+func packagesInTypeExpr(typeExpr string) []string {
+	expr, err := parser.ParseExpr(typeExpr)
+	if err != nil {
+		return nil
+	}
+
+	var packageNames []string
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selectorExpr, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selectorExpr.X.(*ast.Ident); ok {
+			packageNames = append(packageNames, ident.Name)
+		}
+		return false
+	})
+
+	return packageNames
+}
+
+// visitTypeIdents calls visit for each unqualified identifier referring to a type (or constant, e.g. array lengths) in a type expression.
+// Parameter, result, field and method names are skipped.
+func visitTypeIdents(node ast.Node, visit func(ident *ast.Ident)) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr:
+			// already qualified
+			return false
+		case *ast.Field:
+			// skip the names, only visit the type
+			visitTypeIdents(n.Type, visit)
+			return false
+		case *ast.KeyValueExpr:
+			// skip struct field names in composite literals, e.g. `Item{Name: "x"}`
+			if _, ok := n.Key.(*ast.Ident); !ok {
+				visitTypeIdents(n.Key, visit)
+			}
+			visitTypeIdents(n.Value, visit)
+			return false
+		case *ast.Ident:
+			visit(n)
+		}
+		return true
+	})
+}
@@ -0,0 +1,101 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_qualifyTypeExpr(t *testing.T) {
+	tests := map[string]string{
+		"DriveMode":                        "example.DriveMode",
+		"[]*DriveMode":                     "[]*example.DriveMode",
+		"map[string]DriveMode":             "map[string]example.DriveMode",
+		"extrapkg.Error":                   "extrapkg.Error",
+		"func(a, b DriveMode) error":       "func(a, b example.DriveMode) error",
+		"chan<- func() (int, DriveMode)":   "chan<- func() (int, example.DriveMode)",
+		"interface{ Mode() DriveMode }":    "interface{ Mode() example.DriveMode }",
+		"[NumWheels]bool":                  "[example.NumWheels]bool",
+		"func(format string, args ...any)": "func(format string, args ...any)",
+	}
+	for typeExpr, expected := range tests {
+		qualified, err := qualifyTypeExpr(typeExpr, "example", nil)
+		require.NoError(t, err, typeExpr)
+		assert.Equal(t, expected, qualified, typeExpr)
+	}
+
+	_, err := qualifyTypeExpr("func(mode driveMode)", "example", nil)
+	assert.EqualError(t, err, "driveMode is unexported, so it can't be used outside package example")
+}
+
+func Test_packagesInTypeExpr(t *testing.T) {
+	assert.Equal(t, []string{"extrapkg"}, packagesInTypeExpr("[]extrapkg.Error"))
+	assert.Equal(t, []string{"extrapkg", "io"}, packagesInTypeExpr("func(a, b string) (extrapkg.Error, io.Reader)"))
+	assert.Empty(t, packagesInTypeExpr("map[string]DriveMode"))
+}
+
+func TestWriteMockType_outPackage(t *testing.T) {
+	sourceCode := `package example
+
+import "io"
+
+type DriveMode int
+
+type Vehicle interface {
+	Drive(mode DriveMode, cargo []io.Reader) (*DriveMode, error)
+	io.Closer
+	SecondInterface
+}
+`
+	typeData, err := GetMethodsForType(sourceCode, "Vehicle")
+	require.NoError(t, err)
+
+	options := Options{OutPackage: "mocks", PackageImportPath: "github.com/jamesrr39/go-mockgen-tool/example"}
+	mockText, err := WriteMockType("Vehicle", typeData, options)
+	require.NoError(t, err)
+
+	assert.Contains(t, mockText, `package mocks
+
+import (
+	"github.com/jamesrr39/go-mockgen-tool/example"
+	"io"
+	"sync"
+)
+
+// MockVehicle is a mock implementation of [example.Vehicle].
+type MockVehicle struct {
+	// DriveFunc is called by [MockVehicle.Drive].
+	DriveFunc func(mode example.DriveMode, cargo []io.Reader) (*example.DriveMode, error)
+	io.Closer
+	example.SecondInterface
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Drive []MockVehicleDriveCall
+	}
+}
+
+var (
+	// compile time checks that *MockVehicle implements the interface and the interfaces it embeds
+	_ example.Vehicle         = &MockVehicle{}
+	_ io.Closer               = &MockVehicle{}
+	_ example.SecondInterface = &MockVehicle{}
+)
+`)
+	assert.Contains(t, mockText, "func (o *MockVehicle) Drive(mode example.DriveMode, cargo []io.Reader) (*example.DriveMode, error) {")
+
+	t.Run("unexported method", func(t *testing.T) {
+		typeData, err := GetMethodsForType("package example\ntype Vehicle interface {\n\tname() string\n}\n", "Vehicle")
+		require.NoError(t, err)
+
+		_, err = WriteMockType("Vehicle", typeData, options)
+		assert.EqualError(t, err, "method name is unexported, so it can't be implemented outside package example")
+	})
+
+	t.Run("no import path", func(t *testing.T) {
+		_, err := WriteMockType("Vehicle", typeData, Options{OutPackage: "mocks"})
+		assert.Error(t, err)
+	})
+}
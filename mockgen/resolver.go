@@ -0,0 +1,340 @@
+package mockgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Resolver finds the source directories of packages from their import paths, following the go command's rules for
+// workspaces (go.work files), replace directives, vendor directories, the module cache and the standard library.
+// It never accesses the network, so modules that haven't been downloaded can't be resolved.
+type Resolver struct {
+	goRoot   string
+	modCache string
+	// localModules are the modules whose source is edited locally: the modules in the workspace, or the main module
+	localModules []*modFile
+	// replaces are the replace directives of the workspace and its modules, in order of precedence
+	replaces []modReplace
+	// requires are the versions of the required modules, by module path
+	requires map[string]string
+	// vendorDir is the vendor directory of the main module, if its dependencies are vendored
+	vendorDir string
+}
+
+// NewResolver creates a resolver for the module (or workspace) the directory is in.
+// Outside of a module, only standard library packages can be resolved.
+func NewResolver(dirPath string) (*Resolver, error) {
+	dirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := &Resolver{
+		goRoot:   goRoot(),
+		modCache: modCache(),
+		requires: make(map[string]string),
+	}
+
+	workFilePath, err := findWorkFile(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if workFilePath != "" {
+		workFile, err := parseModFile(workFilePath)
+		if err != nil {
+			return nil, err
+		}
+		// replace directives in the go.work file take precedence over the ones in the modules
+		resolver.replaces = append(resolver.replaces, workFile.Replaces...)
+		for _, useDirPath := range workFile.Uses {
+			mod, err := readModFile(filepath.Join(useDirPath, "go.mod"))
+			if err != nil {
+				return nil, err
+			}
+			resolver.addLocalModule(mod)
+		}
+		return resolver, nil
+	}
+
+	mod, err := findModFile(dirPath)
+	if err != nil {
+		if err == ErrNoModule {
+			return resolver, nil
+		}
+		return nil, err
+	}
+	resolver.addLocalModule(mod)
+
+	vendorDir := filepath.Join(mod.Dir, "vendor")
+	_, err = os.Stat(filepath.Join(vendorDir, "modules.txt"))
+	if err == nil && isVendorModeEnabled() {
+		resolver.vendorDir = vendorDir
+	}
+
+	return resolver, nil
+}
+
+func (resolver *Resolver) addLocalModule(mod *modFile) {
+	resolver.localModules = append(resolver.localModules, mod)
+	resolver.replaces = append(resolver.replaces, mod.Replaces...)
+	for modulePath, version := range mod.Requires {
+		if _, ok := resolver.requires[modulePath]; !ok {
+			resolver.requires[modulePath] = version
+		}
+	}
+}
+
+// DirForImportPath returns the directory with the source of the package with the import path
+func (resolver *Resolver) DirForImportPath(importPath string) (string, error) {
+	if isStandardImportPath(importPath) {
+		dirPath := filepath.Join(resolver.goRoot, "src", filepath.FromSlash(importPath))
+		return dirPath, checkPackageDir(importPath, dirPath)
+	}
+
+	modulePath := resolver.findModulePath(importPath)
+	if modulePath == "" {
+		return "", fmt.Errorf("no module provides package %s", importPath)
+	}
+
+	moduleDirPath, err := resolver.moduleDir(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(importPath, modulePath), "/")
+	dirPath := filepath.Join(moduleDirPath, filepath.FromSlash(relativePath))
+
+	return dirPath, checkPackageDir(importPath, dirPath)
+}
+
+// ImportPathForDir returns the import path of the package in the directory, taking into account the modules of the workspace
+func (resolver *Resolver) ImportPathForDir(dirPath string) (string, error) {
+	dirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return "", err
+	}
+
+	// the innermost local module containing the directory
+	var containingModule *modFile
+	for _, mod := range resolver.localModules {
+		if !isInDir(dirPath, mod.Dir) {
+			continue
+		}
+		if containingModule == nil || len(mod.Dir) > len(containingModule.Dir) {
+			containingModule = mod
+		}
+	}
+
+	if containingModule == nil {
+		return ImportPathForDir(dirPath)
+	}
+
+	return containingModule.importPathForDir(dirPath)
+}
+
+// findModulePath returns the path of the module providing the package, i.e. the longest known module path that the import path is in
+func (resolver *Resolver) findModulePath(importPath string) string {
+	var modulePaths []string
+	for _, mod := range resolver.localModules {
+		modulePaths = append(modulePaths, mod.ModulePath)
+	}
+	for _, replace := range resolver.replaces {
+		modulePaths = append(modulePaths, replace.OldPath)
+	}
+	for modulePath := range resolver.requires {
+		modulePaths = append(modulePaths, modulePath)
+	}
+
+	var found string
+	for _, modulePath := range modulePaths {
+		if importPath != modulePath && !strings.HasPrefix(importPath, modulePath+"/") {
+			continue
+		}
+		if len(modulePath) > len(found) {
+			found = modulePath
+		}
+	}
+
+	return found
+}
+
+// moduleDir returns the directory with the source of the module
+func (resolver *Resolver) moduleDir(modulePath string) (string, error) {
+	for _, mod := range resolver.localModules {
+		if mod.ModulePath == modulePath {
+			return mod.Dir, nil
+		}
+	}
+
+	version := resolver.requires[modulePath]
+	for _, replace := range resolver.replaces {
+		if replace.OldPath != modulePath || (replace.OldVersion != "" && replace.OldVersion != version) {
+			continue
+		}
+		if replace.NewVersion == "" {
+			// replaced by a directory
+			return replace.NewPath, nil
+		}
+		return resolver.modCacheDir(replace.NewPath, replace.NewVersion), nil
+	}
+
+	if resolver.vendorDir != "" {
+		return filepath.Join(resolver.vendorDir, filepath.FromSlash(modulePath)), nil
+	}
+
+	if version == "" {
+		return "", fmt.Errorf("module %s is not required by any module in the workspace", modulePath)
+	}
+
+	return resolver.modCacheDir(modulePath, version), nil
+}
+
+// modCacheDir returns the directory of the module version in the module cache
+func (resolver *Resolver) modCacheDir(modulePath, version string) string {
+	return filepath.Join(resolver.modCache, filepath.FromSlash(escapeModulePath(modulePath))+"@"+escapeModulePath(version))
+}
+
+// checkPackageDir returns an error if the package's directory doesn't exist, e.g. because the module hasn't been downloaded
+func checkPackageDir(importPath, dirPath string) error {
+	fileInfo, err := os.Stat(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("package %s not found in %s. If it is in a module that hasn't been downloaded, run `go mod download`", importPath, dirPath)
+		}
+		return err
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("package %s: %s is not a directory", importPath, dirPath)
+	}
+
+	return nil
+}
+
+// findWorkFile returns the path of the go.work file for the directory, from the GOWORK environment variable or the directory and its parents.
+// It returns an empty path if there is no workspace.
+func findWorkFile(dirPath string) (string, error) {
+	switch goWork := os.Getenv("GOWORK"); goWork {
+	case "off":
+		return "", nil
+	case "":
+		return findFileUpwards(dirPath, "go.work")
+	default:
+		return goWork, nil
+	}
+}
+
+// isStandardImportPath returns true if the import path is of a standard library package, i.e. its first element has no dot
+func isStandardImportPath(importPath string) bool {
+	firstElement := importPath
+	slashIndex := strings.Index(importPath, "/")
+	if slashIndex != -1 {
+		firstElement = importPath[:slashIndex]
+	}
+
+	return !strings.Contains(firstElement, ".")
+}
+
+// isVendorModeEnabled returns false if vendor directories are ignored because of the -mod flag in GOFLAGS
+func isVendorModeEnabled() bool {
+	for _, flag := range strings.Fields(os.Getenv("GOFLAGS")) {
+		if flag == "-mod=mod" || flag == "-mod=readonly" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isInDir returns true if the (absolute) path is the directory or inside it
+func isInDir(path, dirPath string) bool {
+	return path == dirPath || strings.HasPrefix(path, dirPath+string(filepath.Separator))
+}
+
+// escapeModulePath escapes a module path or version for the module cache, where upper case letters are written as `!` and the lower case letter
+func escapeModulePath(modulePath string) string {
+	var builder strings.Builder
+	for _, r := range modulePath {
+		if 'A' <= r && r <= 'Z' {
+			builder.WriteRune('!')
+			r += 'a' - 'A'
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// goEnvCache is the output of `go env` for the directories the resolver needs, by the environment variables it depends on,
+// so that the go command is run once for all the resolvers created in a run
+var goEnvCache = struct {
+	mu     sync.Mutex
+	values map[string]map[string]string
+}{values: make(map[string]map[string]string)}
+
+// goEnv returns GOROOT and GOMODCACHE from `go env`. It is empty if the go command can't be run.
+func goEnv() map[string]string {
+	var keyValues []string
+	for _, name := range []string{"PATH", "GOROOT", "GOMODCACHE", "GOPATH", "GOENV"} {
+		keyValues = append(keyValues, os.Getenv(name))
+	}
+	key := strings.Join(keyValues, "\x00")
+
+	goEnvCache.mu.Lock()
+	defer goEnvCache.mu.Unlock()
+	if values, ok := goEnvCache.values[key]; ok {
+		return values
+	}
+
+	values := make(map[string]string)
+	output, err := exec.Command("go", "env", "-json", "GOROOT", "GOMODCACHE").Output()
+	if err == nil {
+		err = json.Unmarshal(output, &values)
+		if err != nil {
+			values = make(map[string]string)
+		}
+	}
+	goEnvCache.values[key] = values
+
+	return values
+}
+
+// goRoot returns the root of the Go installation that builds the packages, from `go env GOROOT`, or the GOROOT
+// go/build knows of if the go command can't be run
+func goRoot() string {
+	if goRoot := goEnv()["GOROOT"]; goRoot != "" {
+		return goRoot
+	}
+
+	return build.Default.GOROOT
+}
+
+// modCache returns the module cache directory, from `go env GOMODCACHE`, or from the GOMODCACHE and GOPATH environment
+// variables if the go command can't be run
+func modCache() string {
+	if modCache := goEnv()["GOMODCACHE"]; modCache != "" {
+		return modCache
+	}
+
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache != "" {
+		return modCache
+	}
+
+	goPath := os.Getenv("GOPATH")
+	if goPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		goPath = filepath.Join(homeDir, "go")
+	}
+
+	return filepath.Join(filepath.SplitList(goPath)[0], "pkg", "mod")
+}
@@ -0,0 +1,111 @@
+package mockgen
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestFiles writes the files, by path relative to dirPath
+func writeTestFiles(t *testing.T, dirPath string, files map[string]string) {
+	for filePath, content := range files {
+		filePath = filepath.Join(dirPath, filepath.FromSlash(filePath))
+		err := os.MkdirAll(filepath.Dir(filePath), 0755)
+		require.NoError(t, err)
+		err = ioutil.WriteFile(filePath, []byte(content), 0644)
+		require.NoError(t, err)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-resolver-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	writeTestFiles(t, dirPath, map[string]string{
+		"workspace/go.work":                             "go 1.21\n\nuse (\n\t./a\n\t./b\n)\n\nreplace example.com/c => ../c\n",
+		"workspace/a/go.mod":                            "module example.com/a\n\ngo 1.21\n\nrequire (\n\texample.com/b v0.0.0\n\texample.com/c v1.0.0\n\texample.com/Upper v1.2.0\n)\n",
+		"workspace/a/store/store.go":                    "package store\n",
+		"workspace/b/go.mod":                            "module example.com/b\n\ngo 1.21\n",
+		"workspace/b/health/health.go":                  "package health\n",
+		"c/go.mod":                                      "module example.com/c\n",
+		"c/cache/cache.go":                              "package cache\n",
+		"modcache/example.com/!upper@v1.2.0/pkg/pkg.go": "package pkg\n",
+	})
+
+	t.Setenv("GOMODCACHE", filepath.Join(dirPath, "modcache"))
+
+	resolver, err := NewResolver(filepath.Join(dirPath, "workspace", "a", "store"))
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"example.com/b/health":  filepath.Join(dirPath, "workspace", "b", "health"),
+		"example.com/c/cache":   filepath.Join(dirPath, "c", "cache"),
+		"example.com/Upper/pkg": filepath.Join(dirPath, "modcache", "example.com", "!upper@v1.2.0", "pkg"),
+		"example.com/a/store":   filepath.Join(dirPath, "workspace", "a", "store"),
+	}
+	for importPath, expected := range tests {
+		packageDirPath, err := resolver.DirForImportPath(importPath)
+		require.NoError(t, err, importPath)
+		assert.Equal(t, expected, packageDirPath, importPath)
+	}
+
+	_, err = resolver.DirForImportPath("example.com/d/pkg")
+	assert.EqualError(t, err, "no module provides package example.com/d/pkg")
+
+	_, err = resolver.DirForImportPath("example.com/b/missing")
+	assert.Error(t, err)
+
+	ioDirPath, err := resolver.DirForImportPath("io")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(ioDirPath, "io.go"))
+
+	importPath, err := resolver.ImportPathForDir(filepath.Join(dirPath, "workspace", "b", "health"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/b/health", importPath)
+}
+
+func Test_goRoot(t *testing.T) {
+	_, err := os.Stat(filepath.Join(goRoot(), "src", "io"))
+	assert.NoError(t, err)
+
+	t.Run("without the go command", func(t *testing.T) {
+		t.Setenv("PATH", "")
+
+		assert.Equal(t, build.Default.GOROOT, goRoot())
+	})
+}
+
+func Test_modCache(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-resolver-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	t.Setenv("GOMODCACHE", filepath.Join(dirPath, "modcache"))
+	assert.Equal(t, filepath.Join(dirPath, "modcache"), modCache())
+
+	t.Run("without the go command", func(t *testing.T) {
+		t.Setenv("PATH", "")
+		t.Setenv("GOMODCACHE", "")
+		t.Setenv("GOPATH", filepath.Join(dirPath, "gopath"))
+
+		assert.Equal(t, filepath.Join(dirPath, "gopath", "pkg", "mod"), modCache())
+	})
+}
+
+func Test_goEnv(t *testing.T) {
+	assert.NotEmpty(t, goEnv()["GOROOT"])
+
+	// the go command is run once for the same environment
+	cachedCount := len(goEnvCache.values)
+	goEnv()
+	assert.Equal(t, cachedCount, len(goEnvCache.values))
+
+	t.Setenv("PATH", "")
+	assert.Empty(t, goEnv())
+}
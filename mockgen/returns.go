@@ -0,0 +1,88 @@
+package mockgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasReturns is true if the mock's method has the methods setting its results, see Options.Returns
+func (method MockMethod) HasReturns() bool {
+	return method.Mock.Returns && method.Recorded() && len(method.ReturnTypes) != 0
+}
+
+// ResultsTypeName is the name of the struct type with the results of a call to the method, e.g. `MockVehicleWheelCountResults`,
+// or `MockHandlerResults` for mocks of function types
+func (method MockMethod) ResultsTypeName() string {
+	if method.Mock.Func {
+		return method.Mock.MockName + "Results"
+	}
+
+	return method.Mock.MockName + upperFirst(method.Name) + "Results"
+}
+
+// ResultsType is ResultsTypeName with the type arguments of generic mocks, e.g. `MockCacheGetResults[K, V]`
+func (method MockMethod) ResultsType() string {
+	return method.ResultsTypeName() + method.Mock.TypeArgs()
+}
+
+// ReturnsMethodName is the name of the mock's method setting the results of all the calls to the method, e.g. `WheelCountReturns`.
+// The methods setting the results of a single call and of successive calls add `OnCall` and `Sequence` to it.
+func (method MockMethod) ReturnsMethodName() string {
+	if method.Mock.Func {
+		return "Returns"
+	}
+
+	return method.Name + "Returns"
+}
+
+// ResultFields are the names of the fields of the results type, e.g. `Result0, Result1`
+func (method MockMethod) ResultFields() []string {
+	var fields []string
+	for i := range method.ReturnTypes {
+		fields = append(fields, fmt.Sprintf("Result%d", i))
+	}
+
+	return fields
+}
+
+// ResultsFromVariable are the fields of the results in a variable named results, for returning them, e.g. `results.Result0, results.Result1`
+func (method MockMethod) ResultsFromVariable() string {
+	var values []string
+	for _, field := range method.ResultFields() {
+		values = append(values, "results."+field)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// HasReturns is true if any of the mock's methods have the methods setting their results
+func (mockData *MockData) HasReturns() bool {
+	for _, method := range mockData.Methods {
+		if method.HasReturns() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkReturnsMethodNames checks that the methods setting the results don't have the same names as the mocked methods
+func checkReturnsMethodNames(mockData *MockData) error {
+	methodNames := make(map[string]struct{})
+	for _, method := range mockData.Methods {
+		methodNames[method.Name] = struct{}{}
+	}
+
+	for _, method := range mockData.Methods {
+		if !method.HasReturns() {
+			continue
+		}
+		for _, suffix := range []string{"", "OnCall", "Sequence"} {
+			if _, ok := methodNames[method.ReturnsMethodName()+suffix]; ok {
+				return fmt.Errorf("the mock's %s method, setting the results of %s, has the same name as a method of the interface", method.ReturnsMethodName()+suffix, method.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package mockgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RecordsResults is true if the method records the results of its calls along with the arguments, for spies, see Options.Spy
+func (method MockMethod) RecordsResults() bool {
+	return method.Mock.Spy && method.Recorded() && len(method.ReturnTypes) != 0
+}
+
+// HasResultsType is true if the method has a struct type with the results of a call, see ResultsTypeName
+func (method MockMethod) HasResultsType() bool {
+	return method.HasReturns() || method.RecordsResults()
+}
+
+// ResultNames are the names of the method's results in spies, which records them when the method returns: `result0`, `result1`...,
+// with underscores added if a parameter has the same name
+func (method MockMethod) ResultNames() []string {
+	var names []string
+	for i := range method.ReturnTypes {
+		names = append(names, method.localName(fmt.Sprintf("result%d", i)))
+	}
+
+	return names
+}
+
+// ResultArgNames are ResultNames as a list, e.g. `result0, result1`
+func (method MockMethod) ResultArgNames() string {
+	return strings.Join(method.ResultNames(), ", ")
+}
+
+// NamedResultList is the list of the method's results with ResultNames, e.g. `(result0 int, result1 error)`
+func (method MockMethod) NamedResultList() string {
+	var results []string
+	for i, name := range method.ResultNames() {
+		results = append(results, name+" "+method.ReturnTypes[i].FullTypeName())
+	}
+
+	return "(" + strings.Join(results, ", ") + ")"
+}
+
+// CallIndexVariable is the name of the variable with the index of the call in the recorded calls, for recording its results in spies:
+// `mockCallIndex`, with underscores added if a parameter has the same name
+func (method MockMethod) CallIndexVariable() string {
+	return method.localName("mockCallIndex")
+}
+
+// checkSpy checks that the mock can be a spy
+func checkSpy(mockData *MockData) error {
+	if mockData.DelegateField != "" {
+		return errors.New("spies delegate all the methods without fields to the real implementation, so they can't be partly mocked")
+	}
+
+	for _, method := range mockData.Methods {
+		if !method.RecordsResults() {
+			continue
+		}
+		for _, field := range method.CallFields() {
+			if field.Name == "Results" {
+				return fmt.Errorf("the parameter %s of %s has the same name as the results recorded in %s", field.Param, method.Name, method.CallTypeName())
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package mockgen
+
+import "fmt"
+
+// SetterName is the name of the mock's method setting the method's field in synchronized mocks, e.g. `SetWheelCountFunc`, see Options.Synchronized
+func (method MockMethod) SetterName() string {
+	return "Set" + upperFirst(method.FieldName)
+}
+
+// FuncVariable is the name of the variable the method's field is read into in synchronized mocks: `mockFunc`,
+// with underscores added if a parameter has the same name
+func (method MockMethod) FuncVariable() string {
+	return method.localName("mockFunc")
+}
+
+// localName returns name, with underscores added if a parameter has the same name, for variables declared in the mock's method
+func (method MockMethod) localName(name string) string {
+	paramNames := make(map[string]struct{})
+	for _, paramName := range method.ParamNames() {
+		paramNames[paramName] = struct{}{}
+	}
+
+	for {
+		if _, ok := paramNames[name]; !ok {
+			return name
+		}
+		name += "_"
+	}
+}
+
+// checkSetterNames checks that the setters of synchronized mocks don't have the same names as the mocked methods
+func checkSetterNames(mockData *MockData) error {
+	if !mockData.Synchronized {
+		return nil
+	}
+
+	methodNames := make(map[string]struct{})
+	for _, method := range mockData.Methods {
+		methodNames[method.Name] = struct{}{}
+	}
+
+	for _, method := range mockData.Methods {
+		if _, ok := methodNames[method.SetterName()]; ok && method.Recorded() {
+			return fmt.Errorf("the mock's %s method, setting %s, has the same name as a method of the interface", method.SetterName(), method.FieldName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,852 @@
+package mockgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/build/constraint"
+	"go/format"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// DefaultTemplate is the template used to render mocks when no custom template is given.
+//
+// Templates are executed with a *MockData. The following helper functions are available:
+//
+//	qualifiedType Type       the type name, including the package name, e.g. `extrapkg.Error`
+//	paramList MockMethod     the parameters with their types, e.g. `a int, b ...string`
+//	callArgs MockMethod      the parameter names, for forwarding the call, e.g. `a, b...`
+//	resultList MockMethod    the result types, e.g. `(int, error)`
+//	zeroValue Type           the zero value of the type, e.g. `0`, `nil` or `*new(extrapkg.Error)`
+//	zeroValues MockMethod    the zero values of the results, e.g. `0, nil`
+//	comment string           the text as a Go comment, e.g. a doc comment
+//	exportedName string      the name with its first letter upper cased, e.g. `Test2` for `test2`
+//
+// A custom template can either render the whole file itself, or only redefine some of the blocks below
+// (e.g. `{{define "method"}}...{{end}}`) and keep the rest of the default layout.
+const DefaultTemplate = `{{block "header" .}}{{with .Header}}{{.}}
+
+{{end}}// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.
+{{end}}
+{{block "buildConstraints" .}}{{range .BuildConstraintLines}}{{.}}
+{{end}}{{end}}
+package {{.PackageName}}
+
+{{block "imports" .}}{{if .Imports}}import (
+{{range .Imports}}	{{if .Name}}{{.Name}} {{end}}{{printf "%q" .Path}}
+{{end}})
+{{end}}{{end}}
+{{block "struct" .}}// {{.MockName}} is a mock implementation of {{.InterfaceDocLinks}}.
+{{with .Doc}}//
+{{comment .}}
+{{end}}type {{.MockName}}{{.TypeParamsDecl}} struct {
+{{with .DelegateField}}	// {{.}} is the implementation that the methods without fields are delegated to
+	{{$.InterfaceType}}
+{{end}}{{if .Spy}}	// mockReal is the implementation that the methods without fields are delegated to
+	mockReal {{.InterfaceType}}
+{{end}}{{range .Methods}}{{if not .Annotations.Skip}}	// {{.FieldName}} is called by [{{.Mock.MockName}}.{{.Name}}].
+{{with .Doc}}	//
+{{comment .}}
+{{end}}{{if $.Synchronized}}	//
+	// Set it with [{{.Mock.MockName}}.{{.SetterName}}] once the mock is in use.
+{{end}}	{{.FieldName}} func({{paramList .}}) {{resultList .}}
+{{end}}{{end}}{{if not .DelegateField}}{{range .EmbeddedInterfaces}}	{{.}}
+{{end}}{{end}}{{range .ExtraFields}}	{{.}}
+{{end}}{{if .RecordsCalls}}
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+{{range .Methods}}{{if .Recorded}}		{{.Name}} []{{.CallType}}
+{{end}}{{end}}	}
+{{end}}{{if and .Expectations .RecordsCalls}}	// mockExpectations are the expected calls to the mocked methods, by method name
+	mockExpectations struct {
+{{range .Methods}}{{if .Recorded}}		{{.Name}} []*{{.ExpectationType}}
+{{end}}{{end}}	}
+{{end}}{{if .HasReturns}}	// mockResults are the results set with the Returns methods, by method name
+	mockResults struct {
+{{range .Methods}}{{if .HasReturns}}		{{.Name}} struct {
+			returns  *{{.ResultsType}}
+			onCall   map[int]{{.ResultsType}}
+			sequence []{{.ResultsType}}
+			// calls is the number of calls the results were looked up for
+			calls int
+		}
+{{end}}{{end}}	}
+{{end}}{{if .RecordsOrder}}	// mockRecorder records the order of the calls across mocks, see {{.MockName}}.RecordOrder
+	mockRecorder *callorder.Recorder
+{{end}}{{if .Testing}}	// mockT is the test the mock was created for with {{.ConstructorName}}, if any
+	mockT testing.TB
+{{end}}}
+{{end}}
+{{block "assertions" .}}{{if .Func}}// compile time check that the mock's Call method is a {{.QualifiedInterfaceName}}
+{{if .TypeParams}}func _{{.TypeParamsDecl}}() {
+	var _ {{.InterfaceType}} = (&{{.MockName}}{{.TypeArgs}}{}).Call
+}
+{{else}}var _ {{.InterfaceType}} = (&{{.MockName}}{}).Call
+{{end}}{{else if .TypeParams}}// compile time checks that *{{.MockName}} implements the interface and the interfaces it embeds
+func _{{.TypeParamsDecl}}() {
+{{if not .Interfaces}}	var _ {{.InterfaceType}} = &{{.MockName}}{{.TypeArgs}}{}
+{{end}}{{range .EmbeddedInterfaces}}	var _ {{.}} = &{{$.MockName}}{{$.TypeArgs}}{}
+{{end}}{{range .FlattenedInterfaces}}	var _ {{.}} = &{{$.MockName}}{{$.TypeArgs}}{}
+{{end}}}
+{{else}}var (
+	// compile time checks that *{{.MockName}} implements the interface and the interfaces it embeds
+{{if not .Interfaces}}	_ {{.InterfaceType}} = &{{.MockName}}{}
+{{end}}{{range .EmbeddedInterfaces}}	_ {{.}} = &{{$.MockName}}{}
+{{end}}{{range .FlattenedInterfaces}}	_ {{.}} = &{{$.MockName}}{}
+{{end}})
+{{end}}{{end}}
+{{block "constructor" .}}{{if .Testing}}
+{{if .Spy}}// {{.ConstructorName}} creates a spy for the test, that delegates the methods without fields to real, and records the calls and their results.
+// The test fails when it ends if fields are set for methods that were never called.
+{{else}}// {{.ConstructorName}} creates a mock for the test{{if .DelegateField}}, that delegates the methods without fields to delegate{{end}}.
+// Calls to methods without fields fail the test, and the test fails when it ends if fields are set for methods that were never called.
+{{end}}func {{.ConstructorName}}{{.TypeParamsDecl}}(t testing.TB{{if .DelegateField}}, delegate {{.InterfaceType}}{{end}}{{if .Spy}}, real {{.InterfaceType}}{{end}}) *{{.MockName}}{{.TypeArgs}} {
+	mock := &{{.MockName}}{{.TypeArgs}}{ {{with .DelegateField}}{{.}}: delegate, {{end}}{{if .Spy}}mockReal: real, {{end}}mockT: t}
+{{if .RecordsCalls}}	t.Cleanup(func() {
+		mock.mockMu.Lock()
+		defer mock.mockMu.Unlock()
+{{range .Methods}}{{if .Recorded}}		if mock.{{.FieldName}} != nil && len(mock.mockCalls.{{.Name}}) == 0 {
+			t.Error("{{.Mock.MockName}}.{{.FieldName}} is set, but {{.Mock.MockName}}.{{.Name}} was never called")
+		}
+{{if .HasReturns}}		if (mock.mockResults.{{.Name}}.returns != nil || mock.mockResults.{{.Name}}.onCall != nil || mock.mockResults.{{.Name}}.sequence != nil) && len(mock.mockCalls.{{.Name}}) == 0 {
+			t.Error("results are set for {{.Mock.MockName}}.{{.Name}}, but {{.Mock.MockName}}.{{.Name}} was never called")
+		}
+{{end}}{{if $.Expectations}}		for _, expectation := range mock.mockExpectations.{{.Name}} {
+			if expectation.calls < expectation.times {
+				t.Errorf("missing call to %s: called %d of %d times", expectation, expectation.calls, expectation.times)
+			}
+		}
+{{end}}{{end}}{{end}}	})
+{{end}}	return mock
+}
+{{else if .Spy}}
+// {{.ConstructorName}} creates a spy that delegates the methods without fields to real, and records the calls and their results.
+func {{.ConstructorName}}{{.TypeParamsDecl}}(real {{.InterfaceType}}) *{{.MockName}}{{.TypeArgs}} {
+	return &{{.MockName}}{{.TypeArgs}}{mockReal: real}
+}
+{{else if .DelegateField}}
+// {{.ConstructorName}} creates a mock that delegates the methods without fields to delegate.
+func {{.ConstructorName}}{{.TypeParamsDecl}}(delegate {{.InterfaceType}}) *{{.MockName}}{{.TypeArgs}} {
+	return &{{.MockName}}{{.TypeArgs}}{ {{.DelegateField}}: delegate }
+}
+{{end}}{{end}}
+{{block "funcAccessor" .}}{{if .Func}}
+// {{.InterfaceName}} returns the mock's Call method as a [{{.QualifiedInterfaceName}}], to pass to the code under test.
+func ({{.Receiver}} *{{.MockName}}{{.TypeArgs}}) {{.InterfaceName}}() {{.InterfaceType}} {
+	return {{.Receiver}}.Call
+}
+{{end}}{{end}}
+{{block "recordOrder" .}}{{if .RecordsOrder}}
+// RecordOrder records the calls to the mock with recorder, along with the calls to the other mocks recorded with it,
+// to check their order with [callorder.Recorder.InOrder].
+func ({{.Receiver}} *{{.MockName}}{{.TypeArgs}}) RecordOrder(recorder *callorder.Recorder) {
+	{{.Receiver}}.mockMu.Lock()
+	defer {{.Receiver}}.mockMu.Unlock()
+	{{.Receiver}}.mockRecorder = recorder
+}
+{{end}}{{end}}
+{{range .Methods}}{{template "method" .}}{{if $.Synchronized}}{{template "setter" .}}{{end}}{{template "calls" .}}{{template "returns" .}}{{if $.RecordsOrder}}{{template "calledStep" .}}{{end}}{{if $.Expectations}}{{template "expectations" .}}{{end}}{{end}}
+{{range .ExtraDecls}}
+{{.}}
+{{end}}
+
+{{- define "method"}}
+{{if not .Annotations.Skip -}}
+// {{.Name}} implements {{.DocLink}} by calling {{.FieldName}}{{if .Mock.Spy}}, or the real implementation if it isn't set{{end}}.
+{{- else if .Annotations.DefaultReturn -}}
+// {{.Name}} implements {{.DocLink}} by returning {{.DefaultReturnValues}}. It isn't mocked.
+{{- else -}}
+// {{.Name}} implements {{.DocLink}}. It isn't mocked, and panics when called.
+{{- end}}
+{{with .Doc}}//
+{{comment .}}
+{{end}}func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.Name}}({{paramList .}}) {{if .RecordsResults}}{{.NamedResultList}}{{else}}{{resultList .}}{{end}} {
+{{- if .Annotations.Skip}}
+	{{if .Annotations.DefaultReturn}}return {{.DefaultReturnValues}}{{else}}panic("{{.Name}} is not mocked"){{end}}
+{{- else}}
+	{{.Mock.Receiver}}.mockMu.Lock()
+	{{.Mock.Receiver}}.mockCalls.{{.Name}} = append({{.Mock.Receiver}}.mockCalls.{{.Name}}, {{template "callValue" .}})
+	{{if .Mock.RecordsOrder}}if {{.Mock.Receiver}}.mockRecorder != nil {
+		{{.Mock.Receiver}}.mockRecorder.Record({{.Mock.Receiver}}, "{{.Mock.MockName}}.{{.Name}}", {{.Mock.Receiver}}.mockCalls.{{.Name}}[len({{.Mock.Receiver}}.mockCalls.{{.Name}})-1])
+	}
+	{{end}}{{if .Mock.Synchronized}}{{.FuncVariable}} := {{.Mock.Receiver}}.{{.FieldName}}
+	{{end}}{{if .RecordsResults}}{{.CallIndexVariable}} := len({{.Mock.Receiver}}.mockCalls.{{.Name}}) - 1
+	{{end}}{{.Mock.Receiver}}.mockMu.Unlock()
+{{- if .RecordsResults}}
+	defer func() {
+		{{.Mock.Receiver}}.mockMu.Lock()
+		defer {{.Mock.Receiver}}.mockMu.Unlock()
+		{{.Mock.Receiver}}.mockCalls.{{.Name}}[{{.CallIndexVariable}}].Results = {{.ResultsType}}{ {{- .ResultArgNames -}} }
+	}()
+{{- end}}
+
+	if {{template "funcValue" .}} == nil {
+		{{if .HasReturns}}{{if .Mock.Testing}}if {{.Mock.Receiver}}.mockT != nil {
+			{{.Mock.Receiver}}.mockT.Helper()
+		}
+		{{end}}if results, ok := {{.Mock.Receiver}}.mockResults{{exportedName .Name}}(); ok {
+			return {{.ResultsFromVariable}}
+		}
+		{{end}}{{if .Mock.Expectations}}if {{.Mock.Receiver}}.mockExpects{{exportedName .Name}}() {
+			{{.Mock.Receiver}}.mockT.Helper()
+			{{if .ReturnTypes}}return {{end}}{{.Mock.Receiver}}.mockCallExpected{{exportedName .Name}}({{template "callValue" .}}){{if not .ReturnTypes}}
+			return{{end}}
+		}
+		{{end}}{{if .Mock.Spy}}if {{.Mock.Receiver}}.mockReal != nil {
+			{{if .ReturnTypes}}return {{end}}{{.Mock.Receiver}}.mockReal{{if not .Mock.Func}}.{{.Name}}{{end}}({{callArgs .}}){{if not .ReturnTypes}}
+			return{{end}}
+		}
+		{{end}}{{template "unset" .}}
+	}
+	{{if .ReturnTypes}}return {{end}}{{template "funcValue" .}}({{callArgs .}})
+{{- end}}
+}
+{{end}}
+
+{{- define "funcValue"}}{{if .Mock.Synchronized}}{{.FuncVariable}}{{else}}{{.Mock.Receiver}}.{{.FieldName}}{{end}}{{end}}
+
+{{- define "setter"}}{{if .Recorded}}
+// {{.SetterName}} sets {{.FieldName}}, synchronized with the calls to [{{.Mock.MockName}}.{{.Name}}], so that it can be set while the mock is in use.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.SetterName}}(fn func({{paramList .}}) {{resultList .}}) {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	{{.Mock.Receiver}}.{{.FieldName}} = fn
+}
+{{end}}{{end}}
+
+{{- define "callValue"}}{{.CallType}}{ {{- range $i, $field := .CallFields}}{{if $i}}, {{end}}{{.Name}}: {{.Param}}{{end -}} }{{end}}
+
+{{- define "unset"}}
+{{- if .Annotations.DefaultReturn}}return {{.DefaultReturnValues}}
+{{- else if eq .Mock.OnUnset "zero" "error"}}return{{with .UnsetReturnValues}} {{.}}{{end}}
+{{- else}}{{if .Mock.Testing}}if {{.Mock.Receiver}}.mockT != nil {
+			{{.Mock.Receiver}}.mockT.Helper()
+			{{.Mock.Receiver}}.mockT.Fatal("{{.UnsetMessage}}")
+		}
+		{{end}}panic("{{.FieldName}} not defined")
+{{- end}}
+{{- end}}
+
+{{- define "calls"}}{{if .Recorded}}
+// {{.CallTypeName}} is a call to [{{.Mock.MockName}}.{{.Name}}], with its arguments.
+type {{.CallTypeName}}{{.Mock.TypeParamsDecl}} struct{{if .CallFields}} {
+{{range .CallFields}}	{{.Name}} {{.Type}}
+{{end}}{{if .RecordsResults}}	// Results are the results of the call, once it has returned
+	Results {{.ResultsType}}
+{{end}}}{{else if .RecordsResults}} {
+	// Results are the results of the call, once it has returned
+	Results {{.ResultsType}}
+}{{else}}{}{{end}}
+
+// {{.CallsMethodName}} returns the calls to [{{.Mock.MockName}}.{{.Name}}], in the order they were made.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.CallsMethodName}}() []{{.CallType}} {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	return append([]{{.CallType}}(nil), {{.Mock.Receiver}}.mockCalls.{{.Name}}...)
+}
+
+// {{.CallCountMethodName}} returns the number of calls to [{{.Mock.MockName}}.{{.Name}}].
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.CallCountMethodName}}() int {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	return len({{.Mock.Receiver}}.mockCalls.{{.Name}})
+}
+{{end}}{{end}}
+
+{{- define "calledStep"}}{{if .Recorded}}
+// {{.CalledMethodName}} returns the step of a call to [{{.Mock.MockName}}.{{.Name}}], for [callorder.Recorder.InOrder].
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.CalledMethodName}}() callorder.Step {
+	return callorder.Step{Mock: {{.Mock.Receiver}}, Method: "{{.Mock.MockName}}.{{.Name}}"}
+}
+{{end}}{{end}}
+
+{{- define "returns"}}{{if .HasResultsType}}
+// {{.ResultsTypeName}} are the results of a call to [{{.Mock.MockName}}.{{.Name}}]{{if .HasReturns}}, for [{{.Mock.MockName}}.{{.ReturnsMethodName}}Sequence]{{end}}.
+type {{.ResultsTypeName}}{{.Mock.TypeParamsDecl}} struct {
+{{range $i, $returnType := .ReturnTypes}}	Result{{$i}} {{qualifiedType $returnType}}
+{{end}}}
+{{end}}{{if .HasReturns}}
+// {{.ReturnsMethodName}} sets the results of the calls to [{{.Mock.MockName}}.{{.Name}}], while {{.FieldName}} isn't set.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.ReturnsMethodName}}({{.ResultParams}}) {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	{{.Mock.Receiver}}.mockResults.{{.Name}}.returns = &{{.ResultsType}}{ {{- .ResultArgs -}} }
+}
+
+// {{.ReturnsMethodName}}OnCall sets the results of one call to [{{.Mock.MockName}}.{{.Name}}], while {{.FieldName}} isn't set. call is 0 for the first call.
+// They take precedence over the results set with {{.ReturnsMethodName}} and {{.ReturnsMethodName}}Sequence.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.ReturnsMethodName}}OnCall(call int, {{.ResultParams}}) {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	if {{.Mock.Receiver}}.mockResults.{{.Name}}.onCall == nil {
+		{{.Mock.Receiver}}.mockResults.{{.Name}}.onCall = make(map[int]{{.ResultsType}})
+	}
+	{{.Mock.Receiver}}.mockResults.{{.Name}}.onCall[call] = {{.ResultsType}}{ {{- .ResultArgs -}} }
+}
+
+// {{.ReturnsMethodName}}Sequence sets the results of successive calls to [{{.Mock.MockName}}.{{.Name}}], while {{.FieldName}} isn't set.
+// They take precedence over the results set with {{.ReturnsMethodName}}. Calls after the last results {{if .Mock.Testing}}fail the test, or panic for mocks not created with {{.Mock.ConstructorName}}{{else}}panic{{end}}.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.ReturnsMethodName}}Sequence(results ...{{.ResultsType}}) {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	{{.Mock.Receiver}}.mockResults.{{.Name}}.sequence = append(make([]{{.ResultsType}}, 0, len(results)), results...)
+}
+
+// mockResults{{exportedName .Name}} returns the results set for the next call to {{.Name}}, if any
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) mockResults{{exportedName .Name}}() ({{.ResultsType}}, bool) {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	stubs := &{{.Mock.Receiver}}.mockResults.{{.Name}}
+	call := stubs.calls
+	stubs.calls++
+
+	if results, ok := stubs.onCall[call]; ok {
+		return results, true
+	}
+	if stubs.sequence != nil {
+		if call < len(stubs.sequence) {
+			return stubs.sequence[call], true
+		}
+		message := fmt.Sprintf("{{.Mock.MockName}}.{{.Name}} called %d times, but {{.ReturnsMethodName}}Sequence only has %d results", call+1, len(stubs.sequence))
+		{{if .Mock.Testing}}if {{.Mock.Receiver}}.mockT != nil {
+			{{.Mock.Receiver}}.mockT.Helper()
+			{{.Mock.Receiver}}.mockT.Fatal(message)
+		}
+		{{end}}panic(message)
+	}
+	if stubs.returns != nil {
+		return *stubs.returns, true
+	}
+
+	return {{.ResultsType}}{}, false
+}
+{{end}}{{end}}
+
+{{- define "expectations"}}{{if .Recorded}}
+// {{.ExpectationTypeName}} is an expected call to [{{.Mock.MockName}}.{{.Name}}], added with [{{.Mock.MockName}}.{{.ExpectMethodName}}].
+type {{.ExpectationTypeName}}{{.Mock.TypeParamsDecl}} struct {
+	mock *{{.Mock.MockName}}{{.Mock.TypeArgs}}
+{{if .CallFields}}	matchers struct {
+{{range .CallFields}}		{{.Name}} match.Matcher[{{.Type}}]
+{{end}}	}
+{{end}}	// times is the number of calls expected, or -1 for any number of calls
+	times int
+	calls int
+	do    func({{paramList .}}) {{resultList .}}
+}
+
+// {{.ExpectMethodName}} adds an expected call to [{{.Mock.MockName}}.{{.Name}}]{{if .CallFields}}, with arguments matching the matchers{{end}}.
+// The call is expected once, unless set otherwise with Times or AnyTimes.{{if .ReturnTypes}} It returns the zero values, unless set otherwise with Return or Do.{{end}}
+// Expectations are only used while {{.FieldName}} isn't set, and need a mock created with {{.Mock.ConstructorName}}.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) {{.ExpectMethodName}}({{.MatcherParams}}) *{{.ExpectationType}} {
+	if {{.Mock.Receiver}}.mockT == nil {
+		panic("expectations need a mock created with {{.Mock.ConstructorName}}")
+	}
+
+	expectation := &{{.ExpectationType}}{mock: {{.Mock.Receiver}}, times: 1}
+{{range .CallFields}}	expectation.matchers.{{.Name}} = {{.Param}}
+{{end}}
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	{{.Mock.Receiver}}.mockExpectations.{{.Name}} = append({{.Mock.Receiver}}.mockExpectations.{{.Name}}, expectation)
+	return expectation
+}
+
+// Times sets the number of calls expected.
+func (e *{{.ExpectationType}}) Times(n int) *{{.ExpectationType}} {
+	e.mock.mockMu.Lock()
+	defer e.mock.mockMu.Unlock()
+	e.times = n
+	return e
+}
+
+// AnyTimes allows any number of calls, including none.
+func (e *{{.ExpectationType}}) AnyTimes() *{{.ExpectationType}} {
+	return e.Times(-1)
+}
+{{if .ReturnTypes}}
+// Return sets the results of the call.
+func (e *{{.ExpectationType}}) Return({{.ResultParams}}) *{{.ExpectationType}} {
+	return e.Do(func({{paramList .}}) {{resultList .}} {
+		return {{.ResultArgs}}
+	})
+}
+{{end}}
+// Do sets a function to call with the arguments{{if .ReturnTypes}}, returning the results{{end}}.
+func (e *{{.ExpectationType}}) Do(do func({{paramList .}}) {{resultList .}}) *{{.ExpectationType}} {
+	e.mock.mockMu.Lock()
+	defer e.mock.mockMu.Unlock()
+	e.do = do
+	return e
+}
+
+// String describes the expected call, e.g. for failure messages.
+func (e *{{.ExpectationType}}) String() string {
+	return "{{.Mock.MockName}}.{{.Name}}({{if .CallFields}}" {{- range $i, $field := .CallFields}}{{if $i}} + ", "{{end}} + e.matchers.{{.Name}}.String(){{end}} + "{{end}})"
+}
+
+// matches returns true if the arguments of the call match the expectation
+func (e *{{.ExpectationType}}) matches(call {{.CallType}}) bool {
+	return {{range $i, $field := .CallFields}}{{if $i}} && {{end}}e.matchers.{{.Name}}.Matches(call.{{.Name}}){{else}}true{{end}}
+}
+
+// mockExpects{{exportedName .Name}} returns true if calls to {{.Name}} are expected with {{.ExpectMethodName}}
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) mockExpects{{exportedName .Name}}() bool {
+	{{.Mock.Receiver}}.mockMu.Lock()
+	defer {{.Mock.Receiver}}.mockMu.Unlock()
+	return len({{.Mock.Receiver}}.mockExpectations.{{.Name}}) != 0
+}
+
+// mockCallExpected{{exportedName .Name}} makes the call with the first expectation that it matches, and that expects more calls.
+// A call that doesn't match any fails the test{{if .ReturnTypes}}, and returns the zero values{{end}}.
+func ({{.Mock.Receiver}} *{{.Mock.MockName}}{{.Mock.TypeArgs}}) mockCallExpected{{exportedName .Name}}(call {{.CallType}}) {{resultList .}} {
+	{{.Mock.Receiver}}.mockT.Helper()
+
+	var expected bool
+	var do func({{paramList .}}) {{resultList .}}
+	{{.Mock.Receiver}}.mockMu.Lock()
+	for _, expectation := range {{.Mock.Receiver}}.mockExpectations.{{.Name}} {
+		if (expectation.times < 0 || expectation.calls < expectation.times) && expectation.matches(call) {
+			expectation.calls++
+			expected, do = true, expectation.do
+			break
+		}
+	}
+	{{.Mock.Receiver}}.mockMu.Unlock()
+
+	if !expected {
+		{{.Mock.Receiver}}.mockT.Error("unexpected call to {{.Mock.MockName}}.{{.Name}}({{if .CallFields}}" {{- range $i, $field := .CallFields}}{{if $i}} + ", "{{end}} + match.Format(call.{{.Name}}){{end}} + "{{end}})")
+	}
+	if do == nil {
+		return{{with zeroValues .}} {{.}}{{end}}
+	}
+	{{if .ReturnTypes}}return {{end}}do({{.CallArgsFromCall}})
+}
+{{end}}{{end}}`
+
+// MockData is the data templates are executed with
+type MockData struct {
+	// TypeData is the interface. If the mock is generated in another package, the types in it are qualified with the interface's package name.
+	*TypeData
+	InterfaceName string
+	// QualifiedInterfaceName is the interface name as referred to from the mock's package, e.g. `Vehicle` or `example.Vehicle`
+	QualifiedInterfaceName string
+	// InterfaceType is the interface type as referred to from the mock's package, including the type arguments of generic interfaces,
+	// e.g. `example.Cache[K, V]`. It is the interface literal for anonymous interfaces.
+	InterfaceType string
+	// MockName is the name of the generated struct type, e.g. `MockVehicle`
+	MockName string
+	// Receiver is the receiver name used in the mock's methods, e.g. `o`
+	Receiver string
+	// ConstructorName is the name of the mock's constructor function, if it has one, e.g. `NewMockVehicle`
+	ConstructorName string
+	// OnUnset is what the methods do when they are called without their fields set
+	OnUnset OnUnset
+	// Expectations is true if the mock has typed expectations, see Options.Expectations
+	Expectations bool
+	// Testing is true if the mock's constructor takes the testing.TB it reports test failures to, see Options.Testing
+	Testing bool
+	// Returns is true if the mock has methods setting the results of its methods, see Options.Returns
+	Returns bool
+	// Order is true if the mock can record the order of the calls across mocks, see Options.Order
+	Order bool
+	// Synchronized is true if the mock's fields can be set with setters while the mock is in use, see Options.Synchronized
+	Synchronized bool
+	// Spy is true if the mock delegates the methods without fields to a real implementation, and records the results of the calls, see Options.Spy
+	Spy bool
+	// DelegateField is the name of the embedded field with the implementation of the interface that the methods without fields
+	// are delegated to, when only some of the methods are mocked (see Options.Methods). Empty if all the methods are mocked.
+	DelegateField string
+	// Header is the header text from the options, as comments
+	Header string
+	// BuildConstraintLines are the `//go:build` and `// +build` lines for the mock file
+	BuildConstraintLines []string
+	Imports              []Import
+	Methods              []MockMethod
+	// ExtraFields and ExtraDecls are the struct fields and top-level declarations contributed by extensions
+	ExtraFields []string
+	ExtraDecls  []string
+}
+
+// MockMethod is a method of the interface, along with the names used for it in the mock
+type MockMethod struct {
+	Method
+	// Mock is the mock the method belongs to
+	Mock *MockData
+	// FieldName is the name of the struct field the method's behaviour is set with, e.g. `NameFunc`.
+	// Methods skipped with `//mockgen:skip` have no field.
+	FieldName string
+	// InterfaceName is the interface the method implements, as referred to from the mock's package, e.g. `Vehicle`.
+	// For composite mocks, it is the interface of the ones combined that declares the method, e.g. `io.Closer`.
+	InterfaceName string
+}
+
+// DocLink returns a doc link to the method in the interface, e.g. `[Vehicle.Name]`, or to the function type for mocks of function types, e.g. `[Handler]`.
+// Methods of anonymous interfaces are named in plain text, e.g. `Name of the anonymous interface`.
+func (method MockMethod) DocLink() string {
+	if method.Mock.anonymous() {
+		return method.Name + " of the anonymous interface"
+	}
+	if method.Mock.Func {
+		return "[" + method.InterfaceName + "]"
+	}
+
+	return "[" + method.InterfaceName + "." + method.Name + "]"
+}
+
+type Import struct {
+	// Name is the alias the package is imported with. Empty if no alias is used.
+	Name string
+	Path string
+}
+
+func newMockData(interfaceName string, typeData *TypeData, options Options) (*MockData, error) {
+	naming := options.Naming
+	if options.Spy && naming.MockPrefix == "" && naming.MockSuffix == "" {
+		naming.MockPrefix = DefaultSpyPrefix
+	}
+	mockData := &MockData{
+		TypeData:               typeData,
+		InterfaceName:          interfaceName,
+		QualifiedInterfaceName: interfaceName,
+		InterfaceType:          interfaceName,
+		MockName:               naming.mockName(interfaceName),
+		ConstructorName:        constructorName(naming.mockName(interfaceName)),
+		Receiver:               naming.receiver(),
+		OnUnset:                options.OnUnset,
+		Testing:                options.Testing || options.Expectations,
+		Expectations:           options.Expectations,
+		Returns:                options.Returns,
+		Order:                  options.Order,
+		Synchronized:           options.Synchronized,
+		Spy:                    options.Spy,
+		Header:                 commentText(options.Header),
+	}
+	if mockData.OnUnset == "" {
+		mockData.OnUnset = OnUnsetPanic
+	}
+
+	err := options.OnUnset.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := parseGoVersion(options.GoVersion)
+	if err != nil {
+		return nil, err
+	}
+	err = version.checkTypeData(interfaceName, typeData)
+	if err != nil {
+		return nil, err
+	}
+
+	isOutPackage := options.OutPackage != "" && options.OutPackage != typeData.PackageName
+	if isOutPackage {
+		outTypeData, err := qualifyTypeData(typeData, options.OutPackage)
+		if err != nil {
+			return nil, err
+		}
+		mockData.TypeData = outTypeData
+		mockData.QualifiedInterfaceName = typeData.PackageName + "." + interfaceName
+		mockData.InterfaceType = mockData.QualifiedInterfaceName
+	}
+	mockData.InterfaceType += mockData.TypeArgs()
+	if mockData.Literal != "" {
+		mockData.InterfaceType = mockData.Literal
+	}
+
+	mockData.TypeData, err = rewriteTypeData(mockData.TypeData, version.rewriteEmptyInterfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	var buildConstraint constraint.Expr
+	for _, buildConstraintText := range []string{typeData.BuildConstraint, options.BuildConstraint} {
+		if buildConstraintText == "" {
+			continue
+		}
+		expr, err := parseBuildConstraint(buildConstraintText)
+		if err != nil {
+			return nil, err
+		}
+		buildConstraint = andBuildConstraints(buildConstraint, expr)
+	}
+
+	mockData.BuildConstraintLines, err = buildConstraintLines(buildConstraint, version.needsPlusBuildLines())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, method := range mockData.TypeData.Methods {
+		fieldName := method.Annotations.FieldName
+		if fieldName == "" {
+			fieldName = naming.fieldName(method.Name)
+		}
+		methodInterfaceName := mockData.QualifiedInterfaceName
+		if len(mockData.Interfaces) != 0 {
+			methodInterfaceName = method.Interface
+		}
+		mockData.Methods = append(mockData.Methods, MockMethod{
+			Method:        method,
+			Mock:          mockData,
+			FieldName:     fieldName,
+			InterfaceName: methodInterfaceName,
+		})
+	}
+
+	var delegated bool
+	mockData.Methods, delegated, err = selectMethods(mockData.Methods, options)
+	if err != nil {
+		return nil, err
+	}
+	mockData.Receiver, err = naming.receiverForMethods(mockData.Methods)
+	if err != nil {
+		return nil, err
+	}
+	if delegated {
+		if mockData.Func {
+			return nil, errors.New("function types can't be partly mocked, as they only have one method")
+		}
+		if len(mockData.Interfaces) != 0 {
+			return nil, errors.New("mocks of several interfaces can't be partly mocked, as the interfaces can't be embedded in the mock to delegate the other methods to")
+		}
+		if mockData.Literal != "" {
+			return nil, errors.New("anonymous interfaces can't be partly mocked, as they can't be embedded in the mock to delegate the other methods to")
+		}
+		mockData.DelegateField = interfaceName
+	}
+	if mockData.Spy {
+		err = checkSpy(mockData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = checkReturnsMethodNames(mockData)
+	if err != nil {
+		return nil, err
+	}
+	err = checkSetterNames(mockData)
+	if err != nil {
+		return nil, err
+	}
+	err = checkCallMethodNames(mockData)
+	if err != nil {
+		return nil, err
+	}
+	err = checkFuncAccessorName(mockData)
+	if err != nil {
+		return nil, err
+	}
+
+	usedPackageNames := mockData.usedPackageNames()
+	for _, im := range typeData.Imports {
+		if _, ok := usedPackageNames[importShortName(im)]; !ok {
+			// only used by delegated methods
+			continue
+		}
+		var name string
+		if im.Name != nil {
+			name = im.Name.Name
+		}
+		path, err := strconv.Unquote(im.Path.Value)
+		if err != nil {
+			path = im.Path.Value
+		}
+		mockData.addImport(Import{Name: name, Path: path})
+	}
+	if isOutPackage {
+		if options.PackageImportPath == "" {
+			return nil, fmt.Errorf("the import path of package %s is needed to generate the mock in package %s", typeData.PackageName, options.OutPackage)
+		}
+		sourcePackageImport := Import{Path: options.PackageImportPath}
+		if path.Base(options.PackageImportPath) != typeData.PackageName {
+			sourcePackageImport.Name = typeData.PackageName
+		}
+		if _, ok := usedPackageNames[typeData.PackageName]; ok {
+			mockData.addImport(sourcePackageImport)
+		}
+	}
+	if mockData.RecordsCalls() {
+		mockData.addImport(Import{Path: "sync"})
+	}
+	if mockData.Testing {
+		mockData.addImport(Import{Path: "testing"})
+	}
+	if mockData.HasReturns() {
+		mockData.addImport(Import{Path: "fmt"})
+	}
+	if mockData.RecordsOrder() {
+		mockData.addImport(Import{Path: callOrderPackagePath})
+		err = checkOrder(mockData)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if mockData.Expectations && mockData.RecordsCalls() {
+		mockData.addImport(Import{Path: matchPackagePath})
+		err = checkExpectations(mockData, version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, method := range mockData.Methods {
+		if !method.Annotations.Skip && method.ReturnsErrorWhenUnset() {
+			mockData.addImport(Import{Path: "errors"})
+		}
+	}
+
+	return mockData, nil
+}
+
+// usedPackageNames returns the names of the packages referred to by the mocked methods and the interfaces the mock implements
+func (mockData *MockData) usedPackageNames() map[string]struct{} {
+	var typeExprs []string
+	for _, method := range mockData.Methods {
+		for _, t := range append(append([]Type(nil), method.Params...), method.ReturnTypes...) {
+			typeExprs = append(typeExprs, t.FullTypeName())
+		}
+		typeExprs = append(typeExprs, method.Annotations.DefaultReturn...)
+	}
+	for _, typeParam := range mockData.TypeParams {
+		typeExprs = append(typeExprs, typeParam.FullTypeName())
+	}
+	typeExprs = append(typeExprs, mockData.EmbeddedInterfaces...)
+	typeExprs = append(typeExprs, mockData.FlattenedInterfaces...)
+	typeExprs = append(typeExprs, mockData.InterfaceType)
+
+	packageNames := make(map[string]struct{})
+	for _, typeExpr := range typeExprs {
+		for _, packageName := range packagesInTypeExpr(typeExpr) {
+			packageNames[packageName] = struct{}{}
+		}
+	}
+
+	return packageNames
+}
+
+// addImport adds the import, if it isn't already imported
+func (mockData *MockData) addImport(im Import) {
+	for _, existingImport := range mockData.Imports {
+		if existingImport == im {
+			return
+		}
+	}
+
+	mockData.Imports = append(mockData.Imports, im)
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"qualifiedType": func(t Type) string {
+			return t.FullTypeName()
+		},
+		"paramList": func(method MockMethod) string {
+			return method.ParamsWithTypes()
+		},
+		"callArgs": func(method MockMethod) string {
+			var args []string
+			for i, paramName := range method.ParamNames() {
+				if i == len(method.Params)-1 && method.Params[i].Variadic {
+					paramName += "..."
+				}
+				args = append(args, paramName)
+			}
+			return strings.Join(args, ", ")
+		},
+		"resultList": func(method MockMethod) string {
+			return strings.TrimSpace(method.ReturnTypesAsString())
+		},
+		"zeroValue": func(t Type) string {
+			return zeroValue(t.FullTypeName())
+		},
+		"comment":      comment,
+		"exportedName": upperFirst,
+		"zeroValues": func(method MockMethod) string {
+			var zeroValues []string
+			for _, returnType := range method.ReturnTypes {
+				zeroValues = append(zeroValues, zeroValue(returnType.FullTypeName()))
+			}
+			return strings.Join(zeroValues, ", ")
+		},
+	}
+}
+
+// renderMockTemplate executes the template (or the default template, if customTemplate is empty) and formats the result
+func renderMockTemplate(mockData *MockData, customTemplate string) (string, error) {
+	tmpl, err := template.New("mock").Funcs(templateFuncs()).Parse(DefaultTemplate)
+	if err != nil {
+		return "", fmt.Errorf("error parsing default template: %s", err)
+	}
+
+	if customTemplate != "" {
+		// blocks defined in the custom template replace the ones in the default template
+		customTmpl, err := tmpl.New("custom").Parse(customTemplate)
+		if err != nil {
+			return "", fmt.Errorf("error parsing template: %s", err)
+		}
+
+		if customTmpl.Tree != nil && !parse.IsEmptyTree(customTmpl.Tree.Root) {
+			// the custom template has its own layout, rather than only overriding blocks
+			tmpl = customTmpl
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buf, mockData)
+	if err != nil {
+		return "", fmt.Errorf("error executing template: %s", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("generated code is not valid Go (%s). Generated code:\n%s", err, buf.String())
+	}
+
+	return string(formatted), nil
+}
+
+// comment turns text, e.g. a doc comment from the interface, into line comments
+func comment(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = "//"
+			continue
+		}
+		lines[i] = "// " + line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// zeroValue returns an expression for the zero value of a type, as written in the source code
+func zeroValue(typeName string) string {
+	switch typeName {
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", "rune", "float32", "float64", "complex64", "complex128":
+		return "0"
+	case "error", "any":
+		return "nil"
+	}
+
+	nilablePrefixes := []string{"*", "[]", "map[", "chan ", "chan<-", "<-chan", "func(", "func (", "interface{", "interface {"}
+	for _, prefix := range nilablePrefixes {
+		if strings.HasPrefix(typeName, prefix) {
+			return "nil"
+		}
+	}
+
+	// named types, structs and arrays
+	return fmt.Sprintf("*new(%s)", typeName)
+}
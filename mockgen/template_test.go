@@ -0,0 +1,217 @@
+package mockgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const templateTestSource = `package example
+
+import "io"
+
+// Logger logs things
+type Logger interface {
+	// Logf logs a formatted message.
+	//
+	// It is safe for concurrent use.
+	Logf(format string, args ...interface{})
+	Count() (int, error)
+	io.Closer
+}
+`
+
+func TestWriteMockType_defaultTemplate(t *testing.T) {
+	typeData, err := GetMethodsForType(templateTestSource, "Logger")
+	require.NoError(t, err)
+
+	mockText, err := WriteMockType("Logger", typeData, Options{})
+	require.NoError(t, err)
+
+	expected := `// Code generated by go-mockgen-tool: https://github.com/jamesrr39/go-mockgen-tool. DO NOT EDIT.
+
+package example
+
+import (
+	"io"
+	"sync"
+)
+
+// MockLogger is a mock implementation of [Logger].
+//
+// Logger logs things
+type MockLogger struct {
+	// LogfFunc is called by [MockLogger.Logf].
+	//
+	// Logf logs a formatted message.
+	//
+	// It is safe for concurrent use.
+	LogfFunc func(format string, args ...interface{})
+	// CountFunc is called by [MockLogger.Count].
+	CountFunc func() (int, error)
+	io.Closer
+
+	mockMu sync.Mutex
+	// mockCalls are the recorded calls to the mocked methods, by method name
+	mockCalls struct {
+		Logf  []MockLoggerLogfCall
+		Count []MockLoggerCountCall
+	}
+}
+
+var (
+	// compile time checks that *MockLogger implements the interface and the interfaces it embeds
+	_ Logger    = &MockLogger{}
+	_ io.Closer = &MockLogger{}
+)
+
+// Logf implements [Logger.Logf] by calling LogfFunc.
+//
+// Logf logs a formatted message.
+//
+// It is safe for concurrent use.
+func (o *MockLogger) Logf(format string, args ...interface{}) {
+	o.mockMu.Lock()
+	o.mockCalls.Logf = append(o.mockCalls.Logf, MockLoggerLogfCall{Format: format, Args: args})
+	o.mockMu.Unlock()
+
+	if o.LogfFunc == nil {
+		panic("LogfFunc not defined")
+	}
+	o.LogfFunc(format, args...)
+}
+
+// MockLoggerLogfCall is a call to [MockLogger.Logf], with its arguments.
+type MockLoggerLogfCall struct {
+	Format string
+	Args   []interface{}
+}
+
+// LogfCalls returns the calls to [MockLogger.Logf], in the order they were made.
+func (o *MockLogger) LogfCalls() []MockLoggerLogfCall {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockLoggerLogfCall(nil), o.mockCalls.Logf...)
+}
+
+// LogfCallCount returns the number of calls to [MockLogger.Logf].
+func (o *MockLogger) LogfCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.Logf)
+}
+
+// Count implements [Logger.Count] by calling CountFunc.
+func (o *MockLogger) Count() (int, error) {
+	o.mockMu.Lock()
+	o.mockCalls.Count = append(o.mockCalls.Count, MockLoggerCountCall{})
+	o.mockMu.Unlock()
+
+	if o.CountFunc == nil {
+		panic("CountFunc not defined")
+	}
+	return o.CountFunc()
+}
+
+// MockLoggerCountCall is a call to [MockLogger.Count], with its arguments.
+type MockLoggerCountCall struct{}
+
+// CountCalls returns the calls to [MockLogger.Count], in the order they were made.
+func (o *MockLogger) CountCalls() []MockLoggerCountCall {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return append([]MockLoggerCountCall(nil), o.mockCalls.Count...)
+}
+
+// CountCallCount returns the number of calls to [MockLogger.Count].
+func (o *MockLogger) CountCallCount() int {
+	o.mockMu.Lock()
+	defer o.mockMu.Unlock()
+	return len(o.mockCalls.Count)
+}
+`
+	assert.Equal(t, expected, mockText)
+}
+
+func TestWriteMockType_customTemplate(t *testing.T) {
+	typeData, err := GetMethodsForType(templateTestSource, "Logger")
+	require.NoError(t, err)
+
+	t.Run("override a block", func(t *testing.T) {
+		customTemplate := `{{define "method"}}
+func (m *{{.Mock.MockName}}) {{.Name}}({{paramList .}}) {{resultList .}} {
+	if m.{{.FieldName}} == nil {
+		{{if .ReturnTypes}}return {{zeroValues .}}{{else}}return{{end}}
+	}
+	{{if .ReturnTypes}}return {{end}}m.{{.FieldName}}({{callArgs .}})
+}
+{{end}}`
+		mockText, err := WriteMockType("Logger", typeData, Options{Template: customTemplate})
+		require.NoError(t, err)
+
+		assert.Contains(t, mockText, "type MockLogger struct {")
+		assert.Contains(t, mockText, `func (m *MockLogger) Count() (int, error) {
+	if m.CountFunc == nil {
+		return 0, nil
+	}
+	return m.CountFunc()
+}`)
+	})
+
+	t.Run("own layout", func(t *testing.T) {
+		customTemplate := `package {{.PackageName}}
+
+// {{.MockName}} mocks {{.InterfaceName}}
+type {{.MockName}} struct{}
+{{range .Methods}}
+func (*{{.Mock.MockName}}) {{.Name}}({{paramList .}}) {{resultList .}} {
+	panic("not implemented")
+}
+{{end}}`
+		mockText, err := WriteMockType("Logger", typeData, Options{Template: customTemplate})
+		require.NoError(t, err)
+
+		assert.Equal(t, `package example
+
+// MockLogger mocks Logger
+type MockLogger struct{}
+
+func (*MockLogger) Logf(format string, args ...interface{}) {
+	panic("not implemented")
+}
+
+func (*MockLogger) Count() (int, error) {
+	panic("not implemented")
+}
+`, mockText)
+	})
+
+	t.Run("invalid Go", func(t *testing.T) {
+		_, err := WriteMockType("Logger", typeData, Options{Template: `package {{.PackageName}} func {`})
+		require.Error(t, err)
+	})
+}
+
+func Test_zeroValue(t *testing.T) {
+	tests := map[string]string{
+		"int":             "0",
+		"string":          `""`,
+		"bool":            "false",
+		"error":           "nil",
+		"*extrapkg.Error": "nil",
+		"[]byte":          "nil",
+		"map[string]int":  "nil",
+		"chan int":        "nil",
+		"func() error":    "nil",
+		"interface{}":     "nil",
+		"extrapkg.Error":  "*new(extrapkg.Error)",
+		"DriveMode":       "*new(DriveMode)",
+		"[4]int":          "*new([4]int)",
+		"channelState":    "*new(channelState)",
+		"struct{ A int }": "*new(struct{ A int })",
+	}
+	for typeName, expected := range tests {
+		assert.Equal(t, expected, zeroValue(typeName), typeName)
+	}
+}
@@ -0,0 +1,59 @@
+package mockgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OnUnset is what the mock's methods do when they are called without their fields set
+type OnUnset string
+
+const (
+	// OnUnsetPanic panics, e.g. with "NameFunc not defined". It is the default.
+	OnUnsetPanic OnUnset = "panic"
+	// OnUnsetZero returns the zero values of the results
+	OnUnsetZero OnUnset = "zero"
+	// OnUnsetError returns the zero values of the results, with an error saying that the field isn't set
+	// if the last result is an error
+	OnUnsetError OnUnset = "error"
+)
+
+func (onUnset OnUnset) validate() error {
+	switch onUnset {
+	case "", OnUnsetPanic, OnUnsetZero, OnUnsetError:
+		return nil
+	default:
+		return fmt.Errorf("unknown behaviour for unset fields %q, expected %s, %s or %s", string(onUnset), OnUnsetPanic, OnUnsetZero, OnUnsetError)
+	}
+}
+
+// ReturnsErrorWhenUnset is true if the method returns an error when its field isn't set, see OnUnsetError
+func (method MockMethod) ReturnsErrorWhenUnset() bool {
+	return method.Mock.OnUnset == OnUnsetError && method.hasErrorResult() && len(method.Annotations.DefaultReturn) == 0
+}
+
+// UnsetReturnValues are the values the method returns when its field isn't set with OnUnsetZero or OnUnsetError,
+// e.g. `0, errors.New("MockVehicle.WheelCount: WheelCountFunc not defined")`
+func (method MockMethod) UnsetReturnValues() string {
+	var values []string
+	for _, returnType := range method.ReturnTypes {
+		values = append(values, zeroValue(returnType.FullTypeName()))
+	}
+	if method.ReturnsErrorWhenUnset() {
+		values[len(values)-1] = fmt.Sprintf("errors.New(%s)", strconv.Quote(method.UnsetMessage()))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// UnsetMessage is the message of the error returned or test failure reported when the method is called without its field set,
+// e.g. `MockVehicle.WheelCount: WheelCountFunc not defined`
+func (method MockMethod) UnsetMessage() string {
+	return fmt.Sprintf("%s.%s: %s not defined", method.Mock.MockName, method.Name, method.FieldName)
+}
+
+// hasErrorResult is true if the last result of the method is an error
+func (method MockMethod) hasErrorResult() bool {
+	return len(method.ReturnTypes) != 0 && method.ReturnTypes[len(method.ReturnTypes)-1].FullTypeName() == "error"
+}
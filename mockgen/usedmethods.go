@@ -0,0 +1,230 @@
+package mockgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UsedMethods type-checks the package in dirPath and returns the names of the exported methods it calls on a concrete type, e.g. `*pgstore.Store`,
+// sorted by name. The type is given as for ExtractInterface.
+//
+// interfaceName is the name of an interface in the package that was generated for the type on a previous run, if any. Calls through it are
+// counted too, so that the interface can be regenerated after the package has been changed to use it rather than the type.
+//
+// Only calls made directly on values of the type are found. Methods used through other interfaces, e.g. by passing the value to a function
+// taking an io.Closer, or through a struct embedding the type, are not.
+//
+// The packages are type-checked as far as possible despite type errors, which are only returned if no calls are found, as they may be why.
+func UsedMethods(dirPath, typeExpr, interfaceName string, resolver *Resolver) ([]string, error) {
+	_, qualifier, typeName, err := parseConcreteType(typeExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	consumerPackage, err := loadGoPackage(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	importer := newSourceImporter(resolver, consumerPackage.fileSet)
+
+	var files []*ast.File
+	for _, file := range consumerPackage.files {
+		files = append(files, file.file)
+	}
+	info := &types.Info{
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	var typeErrors []error
+	config := types.Config{
+		Importer:    importer,
+		FakeImportC: true,
+		// the package is checked as far as possible, as only the calls are needed
+		Error: func(err error) {
+			typeErrors = append(typeErrors, err)
+		},
+	}
+	checkedPackage, _ := config.Check(consumerPackage.name, consumerPackage.fileSet, files, info)
+	typeErrors = append(typeErrors, importer.errors...)
+
+	typePackage := checkedPackage
+	if qualifier != "" {
+		importPath := consumerPackage.importPathForName(qualifier)
+		if importPath == "" {
+			importPath = qualifier
+		}
+		typePackage = importer.packages[importPath]
+		if typePackage == nil {
+			return nil, withTypeErrors(fmt.Errorf("package %s is not imported in %s", importPath, dirPath), typeErrors)
+		}
+	}
+
+	typeObject, ok := typePackage.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, withTypeErrors(fmt.Errorf("type %s not found in package %s", typeName, typePackage.Path()), typeErrors)
+	}
+
+	receiverTypes := []types.Type{typeObject.Type()}
+	if interfaceName != "" {
+		if interfaceObject, ok := checkedPackage.Scope().Lookup(interfaceName).(*types.TypeName); ok {
+			receiverTypes = append(receiverTypes, interfaceObject.Type())
+		}
+	}
+
+	usedMethodNames := make(map[string]struct{})
+	for _, selection := range info.Selections {
+		if selection.Kind() != types.MethodVal && selection.Kind() != types.MethodExpr {
+			continue
+		}
+		if !selection.Obj().Exported() {
+			continue
+		}
+
+		receiverType := selection.Recv()
+		if pointerType, ok := receiverType.(*types.Pointer); ok {
+			receiverType = pointerType.Elem()
+		}
+		for _, t := range receiverTypes {
+			if types.Identical(receiverType, t) {
+				usedMethodNames[selection.Obj().Name()] = struct{}{}
+			}
+		}
+	}
+
+	var methodNames []string
+	for methodName := range usedMethodNames {
+		methodNames = append(methodNames, methodName)
+	}
+	sort.Strings(methodNames)
+
+	if len(methodNames) == 0 && len(typeErrors) != 0 {
+		return nil, withTypeErrors(fmt.Errorf("no calls to the methods of %s found in %s", typeExpr, dirPath), typeErrors)
+	}
+
+	return methodNames, nil
+}
+
+// maxTypeErrors is the number of type errors listed in an error
+const maxTypeErrors = 5
+
+// withTypeErrors adds the type errors found while checking the packages to the error, as they may be its cause
+func withTypeErrors(err error, typeErrors []error) error {
+	if len(typeErrors) == 0 {
+		return err
+	}
+
+	var messages []string
+	for i, typeError := range typeErrors {
+		if i == maxTypeErrors {
+			messages = append(messages, fmt.Sprintf("and %d more", len(typeErrors)-maxTypeErrors))
+			break
+		}
+		messages = append(messages, typeError.Error())
+	}
+
+	return fmt.Errorf("%s. The packages have type errors, which may be why: %s", err, strings.Join(messages, "; "))
+}
+
+// sourceImporter imports packages for type-checking by type-checking their source, which is found with the resolver.
+// Packages with type errors are still imported as far as they could be checked, so that e.g. packages using cgo can be imported.
+type sourceImporter struct {
+	resolver *Resolver
+	fileSet  *token.FileSet
+	// packages are the imported packages, by import path
+	packages map[string]*types.Package
+	// errors are the type errors found in the imported packages
+	errors []error
+}
+
+func newSourceImporter(resolver *Resolver, fileSet *token.FileSet) *sourceImporter {
+	return &sourceImporter{
+		resolver: resolver,
+		fileSet:  fileSet,
+		packages: make(map[string]*types.Package),
+	}
+}
+
+func (importer *sourceImporter) Import(importPath string) (*types.Package, error) {
+	return importer.ImportFrom(importPath, "", 0)
+}
+
+func (importer *sourceImporter) ImportFrom(importPath, fromDirPath string, mode types.ImportMode) (*types.Package, error) {
+	if importPath == "unsafe" {
+		return types.Unsafe, nil
+	}
+
+	// the standard library vendors its dependencies, e.g. golang.org/x/net
+	goRootSrcDirPath := filepath.Join(importer.resolver.goRoot, "src")
+	if !isStandardImportPath(importPath) && isInDir(fromDirPath, goRootSrcDirPath) {
+		importPath = "vendor/" + importPath
+	}
+
+	if pkg, ok := importer.packages[importPath]; ok {
+		return pkg, nil
+	}
+
+	var dirPath string
+	if strings.HasPrefix(importPath, "vendor/") {
+		dirPath = filepath.Join(goRootSrcDirPath, filepath.FromSlash(importPath))
+	} else {
+		var err error
+		dirPath, err = importer.resolver.DirForImportPath(importPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := importer.parseBuildFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := types.Config{
+		Importer:    importer,
+		FakeImportC: true,
+		// only the declarations of imported packages are needed
+		IgnoreFuncBodies: true,
+		Error: func(err error) {
+			importer.errors = append(importer.errors, err)
+		},
+	}
+	pkg, _ := config.Check(importPath, importer.fileSet, files, nil)
+	importer.packages[importPath] = pkg
+
+	return pkg, nil
+}
+
+// parseBuildFiles parses the non-test Go files in the directory that are included in the build for the current platform
+func (importer *sourceImporter) parseBuildFiles(dirPath string) ([]*ast.File, error) {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %q", err)
+	}
+
+	var files []*ast.File
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), ".go") || strings.HasSuffix(fileInfo.Name(), "_test.go") {
+			continue
+		}
+		match, err := build.Default.MatchFile(dirPath, fileInfo.Name())
+		if err != nil || !match {
+			continue
+		}
+
+		file, err := parser.ParseFile(importer.fileSet, filepath.Join(dirPath, fileInfo.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
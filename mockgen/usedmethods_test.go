@@ -0,0 +1,130 @@
+package mockgen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsedMethods(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "mockgen-usedmethods-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	writeTestFiles(t, dirPath, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"pgstore/store.go": `package pgstore
+
+import (
+	"context"
+	"io"
+)
+
+type Item struct{}
+
+type Store struct {
+	io.Closer
+}
+
+func (s *Store) Get(ctx context.Context, key string) (Item, error) { return Item{}, nil }
+
+func (s *Store) Put(ctx context.Context, key string, item Item) error { return nil }
+
+func (s *Store) Delete(ctx context.Context, key string) error { return nil }
+`,
+		"app/app.go": `package app
+
+import (
+	"context"
+
+	db "example.com/shop/pgstore"
+)
+
+type Service struct {
+	store *db.Store
+	cache Cache
+}
+
+func (s *Service) Get(ctx context.Context, key string) (db.Item, error) {
+	defer s.store.Close()
+	return s.store.Get(ctx, key)
+}
+
+func (s *Service) Put(ctx context.Context, key string, item db.Item) error {
+	return s.cache.Put(ctx, key, item)
+}
+`,
+		"app/cache.go": `package app
+
+import (
+	"context"
+
+	db "example.com/shop/pgstore"
+)
+
+// Cache was generated for *db.Store on a previous run
+type Cache interface {
+	Put(ctx context.Context, key string, item db.Item) error
+}
+`,
+		"worker/worker.go": `package worker
+
+import db "example.com/shop/pgstore"
+
+func run(store *db.Store) error {
+	return store.Flush()
+}
+`,
+		"cleanup/cleanup.go": `package cleanup
+
+import db "example.com/shop/pgstore"
+
+func run(store *db.Store) error {
+	defer store.Close()
+	return undefinedError
+}
+`,
+	})
+
+	appDirPath := filepath.Join(dirPath, "app")
+	resolver, err := NewResolver(appDirPath)
+	require.NoError(t, err)
+
+	methodNames, err := UsedMethods(appDirPath, "*db.Store", "", resolver)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Close", "Get"}, methodNames)
+
+	t.Run("calls through the generated interface", func(t *testing.T) {
+		methodNames, err := UsedMethods(appDirPath, "*example.com/shop/pgstore.Store", "Cache", resolver)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Close", "Get", "Put"}, methodNames)
+	})
+
+	t.Run("type errors", func(t *testing.T) {
+		workerDirPath := filepath.Join(dirPath, "worker")
+		_, err := UsedMethods(workerDirPath, "*db.Store", "", resolver)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no calls to the methods of *db.Store found in "+workerDirPath+". The packages have type errors, which may be why: "+filepath.Join(workerDirPath, "worker.go")+":6:15: store.Flush undefined")
+	})
+
+	t.Run("type errors with calls found", func(t *testing.T) {
+		methodNames, err := UsedMethods(filepath.Join(dirPath, "cleanup"), "*db.Store", "", resolver)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Close"}, methodNames)
+	})
+
+	t.Run("extract", func(t *testing.T) {
+		extractedInterface, err := ExtractInterface(appDirPath, "*db.Store", ExtractOptions{InterfaceName: "Store", OnlyUsed: true}, resolver)
+		require.NoError(t, err)
+
+		assert.Contains(t, extractedInterface.Text, `// Store is the interface of the methods of *db.Store used in package app.
+type Store interface {
+	Get(ctx context.Context, key string) (pgstore.Item, error)
+	io.Closer
+}`)
+	})
+}